@@ -0,0 +1,204 @@
+package catastro
+
+import (
+	"bufio"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/pinzlab/sricore/ws"
+)
+
+// Columnas de los archivos del catastro RUC publicados como datos abiertos por el SRI.
+const (
+	columnRUC              = "NUMERO_RUC"
+	columnBusinessName     = "RAZON_SOCIAL"
+	columnStatus           = "ESTADO_CONTRIBUYENTE"
+	columnClass            = "CLASE_CONTRIBUYENTE"
+	columnStartDate        = "FECHA_INICIO_ACTIVIDADES"
+	columnUpdateDate       = "FECHA_ACTUALIZACION"
+	columnCancellationDate = "FECHA_SUSPENSION_DEFINITIVA"
+	columnRestartDate      = "FECHA_REINICIO_ACTIVIDADES"
+	columnAccounting       = "OBLIGADO"
+	columnType             = "TIPO_CONTRIBUYENTE"
+	columnEstablishment    = "NUMERO_ESTABLECIMIENTO"
+	columnTradeName        = "NOMBRE_FANTASIA_COMERCIAL"
+	columnEstablishmentSts = "ESTADO_ESTABLECIMIENTO"
+	columnProvince         = "DESCRIPCION_PROVINCIA_EST"
+	columnCanton           = "DESCRIPCION_CANTON_EST"
+	columnParish           = "DESCRIPCION_PARROQUIA_EST"
+	columnActivity         = "ACTIVIDAD_ECONOMICA"
+	columnWithholding      = "AGENTE_RETENCION"
+	columnSpecial          = "ESPECIAL"
+)
+
+// establishmentStatus traduce las abreviaturas de estado de los establecimientos.
+var establishmentStatus = map[string]string{
+	"ABI": ws.EstablishmentOpen,
+	"CER": ws.EstablishmentClosed,
+}
+
+// entry es la información de un RUC en un archivo del catastro.
+type entry struct {
+	Contributor    *ws.Contributor     `json:"c"`
+	Establishments []*ws.Establishment `json:"e,omitempty"`
+}
+
+// parseCSV lee un archivo del catastro RUC y agrupa sus filas (una por establecimiento)
+// por número de RUC. El separador (| , ; o tabulación) se detecta en la cabecera y las
+// columnas se identifican por su nombre.
+func parseCSV(r io.Reader, entries map[string]*entry) error {
+	reader := bufio.NewReader(r)
+
+	header, err := reader.ReadString('\n')
+	if err != nil && header == "" {
+		return ErrInvalidFile
+	}
+	header = strings.TrimPrefix(header, "\ufeff")
+
+	csvReader := csv.NewReader(io.MultiReader(strings.NewReader(header), reader))
+	csvReader.Comma = separator(header)
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	csvReader.ReuseRecord = true
+
+	names, err := csvReader.Read()
+	if err != nil {
+		return ErrInvalidFile
+	}
+
+	columns := map[string]int{}
+	for i, name := range names {
+		columns[strings.ToUpper(strings.TrimSpace(name))] = i
+	}
+
+	if _, ok := columns[columnRUC]; !ok {
+		return ErrMissingRUC
+	}
+
+	for {
+		row, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return errors.Join(ErrInvalidFile, err)
+		}
+
+		value := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+
+		ruc := value(columnRUC)
+		if len(ruc) != 13 {
+			continue
+		}
+
+		e, ok := entries[ruc]
+		if !ok {
+			e = &entry{Contributor: contributor(ruc, value)}
+			entries[ruc] = e
+		}
+
+		if number := value(columnEstablishment); number != "" {
+			e.Establishments = append(e.Establishments, establishment(number, value))
+		}
+	}
+}
+
+// contributor construye el contribuyente a partir de una fila del catastro.
+func contributor(ruc string, value func(string) string) *ws.Contributor {
+	c := &ws.Contributor{
+		Ruc:                ruc,
+		BusinessName:       value(columnBusinessName),
+		Status:             ws.ContributorStatus(strings.ToUpper(value(columnStatus))),
+		EconomicActivity:   value(columnActivity),
+		Regime:             ws.RegimeGeneral,
+		MustKeepAccounting: yes(value(columnAccounting)),
+		WithholdingAgent:   yes(value(columnWithholding)),
+		SpecialTaxpayer:    yes(value(columnSpecial)),
+	}
+
+	switch kind := strings.ToUpper(value(columnType)); {
+	case strings.HasPrefix(kind, "PERSONA"):
+		c.Type = ws.NaturalPerson
+	case strings.HasPrefix(kind, "SOCIEDAD"):
+		c.Type = ws.Company
+	default:
+		c.Type = ws.ContributorType(kind)
+	}
+
+	switch class := strings.ToUpper(value(columnClass)); {
+	case strings.HasPrefix(class, string(ws.RegimeRIMPE)):
+		c.Regime = ws.RegimeRIMPE
+		if category := strings.TrimSpace(strings.TrimPrefix(class, string(ws.RegimeRIMPE))); category != "" {
+			category = strings.TrimSpace(strings.TrimLeft(category, "-"))
+			c.Category = &category
+		}
+	case class == "ESPECIAL":
+		c.SpecialTaxpayer = true
+	}
+
+	c.TaxpayerDates.StartDate, _ = ws.ParseDate(value(columnStartDate))
+	c.TaxpayerDates.UpdateDate, _ = ws.ParseDate(value(columnUpdateDate))
+	c.TaxpayerDates.CancellationDate, _ = ws.ParseDate(value(columnCancellationDate))
+	c.TaxpayerDates.RestartDate, _ = ws.ParseDate(value(columnRestartDate))
+
+	return c
+}
+
+// establishment construye un establecimiento a partir de una fila del catastro.
+func establishment(number string, value func(string) string) *ws.Establishment {
+	e := &ws.Establishment{
+		Number: number,
+		Status: strings.ToUpper(value(columnEstablishmentSts)),
+		IsMain: number == "001",
+	}
+
+	if status, ok := establishmentStatus[e.Status]; ok {
+		e.Status = status
+	}
+
+	if name := value(columnTradeName); name != "" {
+		e.TradeName = &name
+	}
+
+	var parts []string
+	for _, column := range []string{columnProvince, columnCanton, columnParish} {
+		if part := value(column); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	e.Address = strings.Join(parts, " / ")
+
+	return e
+}
+
+// separator detecta el separador de columnas a partir de la cabecera.
+func separator(header string) rune {
+	best, count := '|', 0
+	for _, candidate := range []rune{'|', '\t', ';', ','} {
+		if n := strings.Count(header, string(candidate)); n > count {
+			best, count = candidate, n
+		}
+	}
+
+	return best
+}
+
+// yes interpreta los indicadores S/N o SI/NO del catastro.
+func yes(value string) sri.Bool {
+	value = strings.ToUpper(value)
+	return sri.Bool(value == "S" || value == "SI")
+}
+
+// isCSV indica si el nombre de archivo corresponde a un archivo de datos del catastro.
+func isCSV(name string) bool {
+	name = strings.ToLower(name)
+	return strings.HasSuffix(name, ".csv") || strings.HasSuffix(name, ".txt")
+}
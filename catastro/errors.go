@@ -0,0 +1,9 @@
+package catastro
+
+import "errors"
+
+var (
+	ErrInvalidFile  = errors.New("El archivo no tiene el formato del catastro RUC del SRI")
+	ErrMissingRUC   = errors.New("El archivo no contiene la columna NUMERO_RUC")
+	ErrCorruptIndex = errors.New("El índice local del catastro está dañado")
+)
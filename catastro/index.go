@@ -0,0 +1,682 @@
+// Package catastro mantiene un índice local del catastro RUC a partir de los archivos
+// de datos abiertos que publica el SRI, para consultar contribuyentes sin conexión.
+package catastro
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"maps"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pinzlab/sricore/ws"
+)
+
+const (
+	dataFile = "catastro.dat"
+	keysFile = "catastro.idx"
+	metaFile = "catastro.json"
+
+	// keySize es la longitud de un RUC.
+	keySize = 13
+
+	// entrySize es el tamaño de una entrada del archivo de claves: RUC y posición.
+	entrySize = keySize + 8
+)
+
+// Metadata describe el contenido del índice.
+type Metadata struct {
+	// UpdatedAt: Fecha de la última importación.
+	UpdatedAt time.Time `json:"updatedAt"`
+
+	// Count: Número de RUC en el índice.
+	Count int `json:"count"`
+
+	// Sources: Resumen SHA-256 de cada archivo importado, por nombre de archivo.
+	Sources map[string]string `json:"sources"`
+}
+
+// ImportResult resume una importación.
+type ImportResult struct {
+	// Files: Archivos importados.
+	Files int
+
+	// Skipped: Archivos omitidos porque no cambiaron desde la última importación.
+	Skipped int
+
+	// RUCs: RUC agregados o actualizados.
+	RUCs int
+
+	// Removed: RUC eliminados porque ya no constan en los archivos reimportados ni en
+	// ningún otro archivo del índice.
+	Removed int
+}
+
+// record es la información de un RUC almacenada en el índice, separada por el nombre del
+// archivo de origen: los RUC con establecimientos en varias provincias constan en varios
+// archivos del catastro.
+type record struct {
+	Sources map[string]*entry `json:"s"`
+}
+
+// merge combina los datos de todos los archivos del RUC: el contribuyente del archivo
+// con la fecha de actualización más reciente y los establecimientos de todos ellos,
+// ordenados por número.
+func (r *record) merge() (*ws.Contributor, []*ws.Establishment) {
+	var contributor *ws.Contributor
+	var establishments []*ws.Establishment
+
+	for _, name := range slices.Sorted(maps.Keys(r.Sources)) {
+		e := r.Sources[name]
+		if contributor == nil || !e.Contributor.TaxpayerDates.UpdateDate.Before(contributor.TaxpayerDates.UpdateDate.Time) {
+			contributor = e.Contributor
+		}
+
+		establishments = append(establishments, e.Establishments...)
+	}
+
+	slices.SortStableFunc(establishments, func(a, b *ws.Establishment) int {
+		return strings.Compare(a.Number, b.Number)
+	})
+
+	return contributor, establishments
+}
+
+// Index es un índice local del catastro RUC almacenado en un directorio.
+//
+// Los datos se guardan ordenados por RUC en un archivo de líneas JSON, con un archivo de
+// claves de tamaño fijo que permite buscar un RUC sin cargar el índice en memoria.
+type Index struct {
+	dir string
+
+	// importMu serializa las importaciones.
+	importMu sync.Mutex
+
+	mu    sync.RWMutex
+	data  *os.File
+	keys  *os.File
+	count int
+	meta  Metadata
+}
+
+// SourceIndex es el nombre de un índice local como origen de datos (ws.DataSource).
+const SourceIndex = "catastro"
+
+var _ ws.DatedLookup = (*Index)(nil)
+
+// Open abre el índice del directorio indicado, creándolo vacío si no existe.
+func Open(dir string) (*Index, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	ix := &Index{dir: dir}
+	if err := ix.load(); err != nil {
+		return nil, err
+	}
+
+	return ix, nil
+}
+
+// load abre los archivos del índice y lee sus metadatos.
+func (ix *Index) load() error {
+	ix.meta = Metadata{Sources: map[string]string{}}
+	ix.count = 0
+
+	data, err := os.ReadFile(filepath.Join(ix.dir, metaFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(data, &ix.meta); err != nil {
+		return errors.Join(ErrCorruptIndex, err)
+	}
+
+	if ix.meta.Sources == nil {
+		ix.meta.Sources = map[string]string{}
+	}
+
+	if ix.data, err = os.Open(filepath.Join(ix.dir, dataFile)); err != nil {
+		return errors.Join(ErrCorruptIndex, err)
+	}
+
+	if ix.keys, err = os.Open(filepath.Join(ix.dir, keysFile)); err != nil {
+		ix.data.Close()
+		return errors.Join(ErrCorruptIndex, err)
+	}
+
+	info, err := ix.keys.Stat()
+	if err != nil {
+		ix.close()
+		return err
+	}
+
+	if info.Size()%entrySize != 0 {
+		ix.close()
+		return ErrCorruptIndex
+	}
+
+	ix.count = int(info.Size() / entrySize)
+
+	return nil
+}
+
+// Close cierra los archivos del índice.
+func (ix *Index) Close() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	return ix.close()
+}
+
+// close cierra los archivos abiertos del índice.
+func (ix *Index) close() error {
+	var errs []error
+	for _, file := range []*os.File{ix.data, ix.keys} {
+		if file != nil {
+			errs = append(errs, file.Close())
+		}
+	}
+
+	ix.data, ix.keys, ix.count = nil, nil, 0
+
+	return errors.Join(errs...)
+}
+
+// UpdatedAt devuelve la fecha de la última importación, o la fecha cero si el índice
+// está vacío.
+func (ix *Index) UpdatedAt() time.Time {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return ix.meta.UpdatedAt
+}
+
+// Len devuelve el número de RUC en el índice.
+func (ix *Index) Len() int {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	return ix.count
+}
+
+// Lookup busca un RUC en el índice. Devuelve ws.ErrContributorNotFound si no existe.
+func (ix *Index) Lookup(ruc string) (*ws.Contributor, []*ws.Establishment, error) {
+	rec, err := ix.find(ruc)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if rec == nil {
+		return nil, nil, ws.ErrContributorNotFound
+	}
+
+	contributor, establishments := rec.merge()
+
+	return contributor, establishments, nil
+}
+
+// find busca un RUC con búsqueda binaria sobre el archivo de claves.
+func (ix *Index) find(ruc string) (*record, error) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+
+	if len(ruc) != keySize || ix.count == 0 {
+		return nil, nil
+	}
+
+	entry := make([]byte, entrySize)
+	read := func(i int) error {
+		_, err := ix.keys.ReadAt(entry, int64(i)*entrySize)
+		return err
+	}
+
+	low, high := 0, ix.count
+	for low < high {
+		mid := int(uint(low+high) >> 1)
+		if err := read(mid); err != nil {
+			return nil, errors.Join(ErrCorruptIndex, err)
+		}
+
+		if string(entry[:keySize]) < ruc {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+
+	if low == ix.count {
+		return nil, nil
+	}
+
+	if err := read(low); err != nil {
+		return nil, errors.Join(ErrCorruptIndex, err)
+	}
+
+	if string(entry[:keySize]) != ruc {
+		return nil, nil
+	}
+
+	offset := int64(binary.BigEndian.Uint64(entry[keySize:]))
+	line, err := bufio.NewReader(io.NewSectionReader(ix.data, offset, math.MaxInt64-offset)).ReadBytes('\n')
+	if err != nil || len(line) <= keySize+1 {
+		return nil, errors.Join(ErrCorruptIndex, err)
+	}
+
+	return parseRecord(line)
+}
+
+// parseRecord lee el registro de una línea de datos ("RUC\tJSON\n").
+func parseRecord(line []byte) (*record, error) {
+	var rec record
+	if err := json.Unmarshal(line[keySize+1:], &rec); err != nil || len(rec.Sources) == 0 {
+		return nil, errors.Join(ErrCorruptIndex, err)
+	}
+
+	return &rec, nil
+}
+
+// CheckRUCContext implementa ws.ContributorLookup.
+func (ix *Index) CheckRUCContext(ctx context.Context, ruc string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+
+	rec, err := ix.find(ruc)
+	return rec != nil, err
+}
+
+// GetContributorsContext implementa ws.ContributorLookup.
+func (ix *Index) GetContributorsContext(ctx context.Context, ruc string) ([]*ws.Contributor, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rec, err := ix.find(ruc)
+	if err != nil || rec == nil {
+		return []*ws.Contributor{}, err
+	}
+
+	contributor, _ := rec.merge()
+
+	return []*ws.Contributor{contributor}, nil
+}
+
+// GetContributorsSource implementa ws.DatedLookup. La fecha de los datos es la de la
+// última importación.
+func (ix *Index) GetContributorsSource(ctx context.Context, ruc string) ([]*ws.Contributor, ws.DataSource, error) {
+	contributors, err := ix.GetContributorsContext(ctx, ruc)
+	return contributors, ws.DataSource{Name: SourceIndex, AsOf: ix.UpdatedAt()}, err
+}
+
+// GetEstablishmentsContext implementa ws.ContributorLookup.
+func (ix *Index) GetEstablishmentsContext(ctx context.Context, ruc string) ([]*ws.Establishment, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	rec, err := ix.find(ruc)
+	if err != nil || rec == nil {
+		return []*ws.Establishment{}, err
+	}
+
+	_, establishments := rec.merge()
+	if establishments == nil {
+		return []*ws.Establishment{}, nil
+	}
+
+	return establishments, nil
+}
+
+// Import agrega al índice los archivos CSV o ZIP del catastro RUC indicados.
+//
+// La importación es incremental: los archivos que no cambiaron desde la última
+// importación (según su nombre y resumen SHA-256) se omiten, y los datos de cada archivo
+// nuevo reemplazan a los que se importaron antes desde un archivo con el mismo nombre,
+// sin afectar a los de otros archivos. Un RUC que consta en varios archivos (por
+// ejemplo, con establecimientos en varias provincias) combina los datos de todos ellos,
+// y un RUC que deja de constar en todos sus archivos se elimina del índice. Las
+// consultas pueden realizarse mientras se importa.
+func (ix *Index) Import(paths ...string) (*ImportResult, error) {
+	ix.importMu.Lock()
+	defer ix.importMu.Unlock()
+
+	result := &ImportResult{}
+	incoming := map[string]*record{}
+	sources := map[string]string{}
+
+	for _, path := range paths {
+		name := filepath.Base(path)
+
+		hash, err := fileHash(path)
+		if err != nil {
+			return nil, err
+		}
+
+		ix.mu.RLock()
+		unchanged := ix.meta.Sources[name] == hash
+		ix.mu.RUnlock()
+
+		if unchanged {
+			result.Skipped++
+			continue
+		}
+
+		entries := map[string]*entry{}
+		if err := parseFile(path, entries); err != nil {
+			return nil, err
+		}
+
+		for ruc, e := range entries {
+			rec, ok := incoming[ruc]
+			if !ok {
+				rec = &record{Sources: map[string]*entry{}}
+				incoming[ruc] = rec
+			}
+			rec.Sources[name] = e
+		}
+
+		sources[name] = hash
+		result.Files++
+	}
+
+	if result.Files == 0 {
+		return result, nil
+	}
+
+	result.RUCs = len(incoming)
+
+	removed, err := ix.rebuild(incoming, sources)
+	if err != nil {
+		return nil, err
+	}
+	result.Removed = removed
+
+	return result, nil
+}
+
+// rebuild combina el índice actual con los registros nuevos y reemplaza sus archivos.
+// Los datos almacenados de los archivos de sources se reemplazan por los de incoming;
+// devuelve el número de RUC eliminados porque ya no constan en ningún archivo.
+func (ix *Index) rebuild(incoming map[string]*record, sources map[string]string) (int, error) {
+	keys := make([]string, 0, len(incoming))
+	for ruc := range incoming {
+		keys = append(keys, ruc)
+	}
+	slices.Sort(keys)
+
+	// Marcas para reconocer sin deserializar las líneas con datos de los archivos reimportados.
+	marks := make([][]byte, 0, len(sources))
+	for name := range sources {
+		mark, err := json.Marshal(name)
+		if err != nil {
+			return 0, err
+		}
+		marks = append(marks, append(mark, ':'))
+	}
+
+	dataTmp, err := os.CreateTemp(ix.dir, dataFile+".*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(dataTmp.Name())
+	defer dataTmp.Close()
+
+	keysTmp, err := os.CreateTemp(ix.dir, keysFile+".*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(keysTmp.Name())
+	defer keysTmp.Close()
+
+	w := &indexWriter{data: bufio.NewWriter(dataTmp), keys: bufio.NewWriter(keysTmp)}
+
+	var old *bufio.Reader
+	if file, err := os.Open(filepath.Join(ix.dir, dataFile)); err == nil {
+		defer file.Close()
+		old = bufio.NewReader(file)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return 0, err
+	}
+
+	next := func() ([]byte, error) {
+		if old == nil {
+			return nil, nil
+		}
+
+		line, err := old.ReadBytes('\n')
+		if errors.Is(err, io.EOF) && len(line) == 0 {
+			return nil, nil
+		}
+		if err != nil || len(line) <= keySize {
+			return nil, errors.Join(ErrCorruptIndex, err)
+		}
+
+		return line, nil
+	}
+
+	replaced := func(line []byte) bool {
+		return slices.ContainsFunc(marks, func(mark []byte) bool { return bytes.Contains(line, mark) })
+	}
+
+	removed := 0
+	line, err := next()
+	for err == nil && (line != nil || len(keys) > 0) {
+		var ruc string
+		var rec *record
+
+		if line != nil && (len(keys) == 0 || string(line[:keySize]) <= keys[0]) {
+			ruc = string(line[:keySize])
+
+			// Los registros que no tienen datos de los archivos importados se copian sin cambios.
+			if (len(keys) == 0 || ruc != keys[0]) && !replaced(line) {
+				if err = w.writeLine(line); err == nil {
+					line, err = next()
+				}
+				continue
+			}
+
+			if rec, err = parseRecord(line); err != nil {
+				break
+			}
+			if line, err = next(); err != nil {
+				break
+			}
+
+			for name := range sources {
+				delete(rec.Sources, name)
+			}
+		} else {
+			ruc = keys[0]
+			rec = &record{Sources: map[string]*entry{}}
+		}
+
+		if len(keys) > 0 && keys[0] == ruc {
+			maps.Copy(rec.Sources, incoming[ruc].Sources)
+			keys = keys[1:]
+		}
+
+		if len(rec.Sources) == 0 {
+			removed++
+			continue
+		}
+
+		err = w.writeRecord(ruc, rec)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if err := w.flush(); err != nil {
+		return 0, err
+	}
+
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	meta := Metadata{UpdatedAt: time.Now(), Count: w.count, Sources: map[string]string{}}
+	for name, hash := range ix.meta.Sources {
+		meta.Sources[name] = hash
+	}
+	for name, hash := range sources {
+		meta.Sources[name] = hash
+	}
+
+	metaData, err := json.MarshalIndent(meta, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+
+	ix.close()
+
+	if err := os.Rename(dataTmp.Name(), filepath.Join(ix.dir, dataFile)); err != nil {
+		return 0, err
+	}
+
+	if err := os.Rename(keysTmp.Name(), filepath.Join(ix.dir, keysFile)); err != nil {
+		return 0, err
+	}
+
+	if err := writeFileAtomic(filepath.Join(ix.dir, metaFile), metaData); err != nil {
+		return 0, err
+	}
+
+	return removed, ix.load()
+}
+
+// indexWriter escribe los archivos de datos y claves de un índice.
+type indexWriter struct {
+	data   *bufio.Writer
+	keys   *bufio.Writer
+	offset int64
+	count  int
+}
+
+// writeRecord escribe el registro de un RUC.
+func (w *indexWriter) writeRecord(ruc string, rec *record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	line := make([]byte, 0, keySize+len(data)+2)
+	line = append(line, ruc...)
+	line = append(line, '\t')
+	line = append(line, data...)
+	line = append(line, '\n')
+
+	return w.writeLine(line)
+}
+
+// writeLine escribe una línea de datos ("RUC\tJSON\n") y su entrada en el archivo de claves.
+func (w *indexWriter) writeLine(line []byte) error {
+	var entry [entrySize]byte
+	copy(entry[:keySize], line[:keySize])
+	binary.BigEndian.PutUint64(entry[keySize:], uint64(w.offset))
+
+	if _, err := w.keys.Write(entry[:]); err != nil {
+		return err
+	}
+
+	n, err := w.data.Write(line)
+	w.offset += int64(n)
+	w.count++
+
+	return err
+}
+
+// flush escribe los datos pendientes en disco.
+func (w *indexWriter) flush() error {
+	return errors.Join(w.data.Flush(), w.keys.Flush())
+}
+
+// parseFile lee un archivo CSV o ZIP del catastro.
+func parseFile(path string, entries map[string]*entry) error {
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		archive, err := zip.OpenReader(path)
+		if err != nil {
+			return errors.Join(ErrInvalidFile, err)
+		}
+		defer archive.Close()
+
+		for _, file := range archive.File {
+			if !isCSV(file.Name) {
+				continue
+			}
+
+			r, err := file.Open()
+			if err != nil {
+				return errors.Join(ErrInvalidFile, err)
+			}
+
+			err = parseCSV(r, entries)
+			r.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return parseCSV(file, entries)
+}
+
+// fileHash calcula el resumen SHA-256 de un archivo.
+func fileHash(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// writeFileAtomic escribe un archivo reemplazándolo de forma atómica.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, bytes.NewReader(data)); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,274 @@
+package catastro
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pinzlab/sricore/ws"
+)
+
+const (
+	header = "NUMERO_RUC|RAZON_SOCIAL|ESTADO_CONTRIBUYENTE|CLASE_CONTRIBUYENTE|FECHA_INICIO_ACTIVIDADES|OBLIGADO|TIPO_CONTRIBUYENTE|NUMERO_ESTABLECIMIENTO|NOMBRE_FANTASIA_COMERCIAL|ESTADO_ESTABLECIMIENTO|DESCRIPCION_PROVINCIA_EST|DESCRIPCION_CANTON_EST|DESCRIPCION_PARROQUIA_EST|ACTIVIDAD_ECONOMICA\n"
+
+	chimborazo = "\ufeff" + header +
+		"0690000512001|EMPRESA ELECTRICA RIOBAMBA SA|ACTIVO|OTROS|1963-05-02 00:00:00.0|S|SOCIEDAD|001|EERSA|ABI|CHIMBORAZO|RIOBAMBA|LIZARZABURU|GENERACION DE ENERGIA ELECTRICA\n" +
+		"0690000512001|EMPRESA ELECTRICA RIOBAMBA SA|ACTIVO|OTROS|1963-05-02 00:00:00.0|S|SOCIEDAD|002||CER|CHIMBORAZO|GUANO|GUANO|GENERACION DE ENERGIA ELECTRICA\n" +
+		"0602910945001|PEREZ LOPEZ JUAN|ACTIVO|RIMPE - NEGOCIO POPULAR|2019-01-10 00:00:00.0|N|PERSONA NATURAL|001|TIENDA JUAN|ABI|CHIMBORAZO|RIOBAMBA|VELASCO|VENTA AL POR MENOR\n"
+
+	pichincha = header +
+		"1790011674001|BANCO PICHINCHA CA|ACTIVO|ESPECIAL|1906-04-11 00:00:00.0|S|SOCIEDAD|001|BANCO PICHINCHA|ABI|PICHINCHA|QUITO|IÑAQUITO|BANCA\n"
+)
+
+// writeFile crea un archivo con el contenido indicado en un directorio temporal.
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+// writeZip crea un archivo ZIP con un archivo CSV.
+func writeZip(t *testing.T, dir, name, csvName, content string) string {
+	path := filepath.Join(dir, name)
+	file, err := os.Create(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	archive := zip.NewWriter(file)
+	w, err := archive.Create(csvName)
+	require.NoError(t, err)
+	_, err = w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, archive.Close())
+
+	return path
+}
+
+func TestIndex_Import(t *testing.T) {
+	data := t.TempDir()
+	ix, err := Open(t.TempDir())
+	require.NoError(t, err)
+	defer ix.Close()
+
+	assert.True(t, ix.UpdatedAt().IsZero())
+	assert.Equal(t, 0, ix.Len())
+
+	result, err := ix.Import(
+		writeFile(t, data, "SRI_RUC_Chimborazo.csv", chimborazo),
+		writeZip(t, data, "SRI_RUC_Pichincha.zip", "SRI_RUC_Pichincha.csv", pichincha),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{Files: 2, RUCs: 3}, result)
+	assert.Equal(t, 3, ix.Len())
+	assert.False(t, ix.UpdatedAt().IsZero())
+
+	contributor, establishments, err := ix.Lookup("0690000512001")
+	require.NoError(t, err)
+	assert.Equal(t, "EMPRESA ELECTRICA RIOBAMBA SA", contributor.BusinessName)
+	assert.True(t, contributor.IsActive())
+	assert.Equal(t, ws.Company, contributor.Type)
+	assert.Equal(t, 1963, contributor.TaxpayerDates.StartDate.Year())
+	require.Len(t, establishments, 2)
+	assert.Equal(t, "ABIERTO", establishments[0].Status)
+	assert.True(t, bool(establishments[0].IsMain))
+	assert.Equal(t, "CHIMBORAZO / RIOBAMBA / LIZARZABURU", establishments[0].Address)
+	assert.Equal(t, "CERRADO", establishments[1].Status)
+	assert.Nil(t, establishments[1].TradeName)
+
+	contributor, _, err = ix.Lookup("0602910945001")
+	require.NoError(t, err)
+	assert.True(t, contributor.IsRIMPE())
+	assert.True(t, contributor.IsNaturalPerson())
+
+	contributor, _, err = ix.Lookup("1790011674001")
+	require.NoError(t, err)
+	assert.True(t, bool(contributor.SpecialTaxpayer))
+
+	_, _, err = ix.Lookup("0999999999001")
+	assert.ErrorIs(t, err, ws.ErrContributorNotFound)
+
+	_, _, err = ix.Lookup("0690000512")
+	assert.ErrorIs(t, err, ws.ErrContributorNotFound)
+}
+
+func TestIndex_ImportIncremental(t *testing.T) {
+	data := t.TempDir()
+	dir := t.TempDir()
+	ix, err := Open(dir)
+	require.NoError(t, err)
+
+	chimborazoPath := writeFile(t, data, "SRI_RUC_Chimborazo.csv", chimborazo)
+	pichinchaPath := writeFile(t, data, "SRI_RUC_Pichincha.csv", pichincha)
+
+	_, err = ix.Import(chimborazoPath, pichinchaPath)
+	require.NoError(t, err)
+	updatedAt := ix.UpdatedAt()
+
+	// Sin cambios: no se reescribe el índice.
+	result, err := ix.Import(chimborazoPath, pichinchaPath)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{Skipped: 2}, result)
+	assert.Equal(t, updatedAt, ix.UpdatedAt())
+
+	// Una nueva versión del archivo reemplaza solo sus RUC.
+	writeFile(t, data, "SRI_RUC_Pichincha.csv", header+
+		"1790011674001|BANCO PICHINCHA CA|SUSPENDIDO|ESPECIAL|1906-04-11 00:00:00.0|S|SOCIEDAD|001|BANCO PICHINCHA|ABI|PICHINCHA|QUITO|IÑAQUITO|BANCA\n"+
+		"1791256115001|CORPORACION FAVORITA CA|ACTIVO|ESPECIAL|1957-06-14 00:00:00.0|S|SOCIEDAD|001|SUPERMAXI|ABI|PICHINCHA|QUITO|IÑAQUITO|SUPERMERCADOS\n")
+
+	result, err = ix.Import(chimborazoPath, pichinchaPath)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{Files: 1, Skipped: 1, RUCs: 2}, result)
+	assert.Equal(t, 4, ix.Len())
+	assert.False(t, ix.UpdatedAt().Before(updatedAt))
+
+	contributor, _, err := ix.Lookup("1790011674001")
+	require.NoError(t, err)
+	assert.Equal(t, ws.ContributorSuspended, contributor.Status)
+
+	require.NoError(t, ix.Close())
+
+	// El índice persiste al reabrirlo.
+	ix, err = Open(dir)
+	require.NoError(t, err)
+	defer ix.Close()
+
+	assert.Equal(t, 4, ix.Len())
+	assert.False(t, ix.UpdatedAt().Before(updatedAt.Truncate(time.Second)))
+
+	for _, ruc := range []string{"0602910945001", "0690000512001", "1790011674001", "1791256115001"} {
+		ok, err := ix.CheckRUCContext(context.Background(), ruc)
+		require.NoError(t, err)
+		assert.True(t, ok, ruc)
+	}
+}
+
+func TestIndex_ImportSharedRUC(t *testing.T) {
+	data := t.TempDir()
+	ix, err := Open(t.TempDir())
+	require.NoError(t, err)
+	defer ix.Close()
+
+	guayasHeader := "NUMERO_RUC|RAZON_SOCIAL|ESTADO_CONTRIBUYENTE|FECHA_ACTUALIZACION|TIPO_CONTRIBUYENTE|NUMERO_ESTABLECIMIENTO|ESTADO_ESTABLECIMIENTO|DESCRIPCION_PROVINCIA_EST|DESCRIPCION_CANTON_EST|DESCRIPCION_PARROQUIA_EST\n"
+
+	chimborazoPath := writeFile(t, data, "SRI_RUC_Chimborazo.csv", chimborazo)
+	guayasPath := writeFile(t, data, "SRI_RUC_Guayas.csv", guayasHeader+
+		"0690000512001|EMPRESA ELECTRICA RIOBAMBA S.A.|ACTIVO|2024-03-01 00:00:00.0|SOCIEDAD|003|ABI|GUAYAS|GUAYAQUIL|TARQUI\n"+
+		"0992339411001|COMERCIAL GUAYAS SA|ACTIVO|2023-01-01 00:00:00.0|SOCIEDAD|001|ABI|GUAYAS|GUAYAQUIL|XIMENA\n")
+
+	result, err := ix.Import(chimborazoPath, guayasPath)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{Files: 2, RUCs: 3}, result)
+	assert.Equal(t, 3, ix.Len())
+
+	// El RUC combina los establecimientos de ambas provincias y toma el contribuyente
+	// del archivo actualizado más recientemente.
+	contributor, establishments, err := ix.Lookup("0690000512001")
+	require.NoError(t, err)
+	assert.Equal(t, "EMPRESA ELECTRICA RIOBAMBA S.A.", contributor.BusinessName)
+	require.Len(t, establishments, 3)
+	assert.Equal(t, "001", establishments[0].Number)
+	assert.Equal(t, "GUAYAS / GUAYAQUIL / TARQUI", establishments[2].Address)
+
+	// Reimportar Guayas reemplaza solo sus datos: el establecimiento 003 ya no consta y
+	// el RUC que solo estaba en Guayas se elimina.
+	writeFile(t, data, "SRI_RUC_Guayas.csv", guayasHeader+
+		"0992339412001|OTRA EMPRESA GUAYAS SA|ACTIVO|2024-01-01 00:00:00.0|SOCIEDAD|001|ABI|GUAYAS|GUAYAQUIL|XIMENA\n")
+
+	result, err = ix.Import(chimborazoPath, guayasPath)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{Files: 1, Skipped: 1, RUCs: 1, Removed: 1}, result)
+	assert.Equal(t, 3, ix.Len())
+
+	contributor, establishments, err = ix.Lookup("0690000512001")
+	require.NoError(t, err)
+	assert.Equal(t, "EMPRESA ELECTRICA RIOBAMBA SA", contributor.BusinessName)
+	assert.Len(t, establishments, 2)
+
+	_, _, err = ix.Lookup("0992339411001")
+	assert.ErrorIs(t, err, ws.ErrContributorNotFound)
+
+	_, _, err = ix.Lookup("0992339412001")
+	assert.NoError(t, err)
+
+	// El RUC se elimina cuando deja de constar también en Chimborazo.
+	writeFile(t, data, "SRI_RUC_Chimborazo.csv", header+
+		"0602910945001|PEREZ LOPEZ JUAN|ACTIVO|RIMPE - NEGOCIO POPULAR|2019-01-10 00:00:00.0|N|PERSONA NATURAL|001|TIENDA JUAN|ABI|CHIMBORAZO|RIOBAMBA|VELASCO|VENTA AL POR MENOR\n")
+
+	result, err = ix.Import(chimborazoPath, guayasPath)
+	require.NoError(t, err)
+	assert.Equal(t, &ImportResult{Files: 1, Skipped: 1, RUCs: 1, Removed: 1}, result)
+	assert.Equal(t, 2, ix.Len())
+
+	_, _, err = ix.Lookup("0690000512001")
+	assert.ErrorIs(t, err, ws.ErrContributorNotFound)
+}
+
+func TestIndex_ContributorLookup(t *testing.T) {
+	ix, err := Open(t.TempDir())
+	require.NoError(t, err)
+	defer ix.Close()
+
+	_, err = ix.Import(writeFile(t, t.TempDir(), "SRI_RUC_Chimborazo.csv", chimborazo))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+
+	contributors, err := ix.GetContributorsContext(ctx, "0690000512001")
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+	assert.Equal(t, "0690000512001", contributors[0].Ruc)
+
+	_, source, err := ix.GetContributorsSource(ctx, "0690000512001")
+	require.NoError(t, err)
+	assert.Equal(t, ws.DataSource{Name: SourceIndex, AsOf: ix.UpdatedAt()}, source)
+
+	contributors, err = ix.GetContributorsContext(ctx, "0999999999001")
+	require.NoError(t, err)
+	assert.Empty(t, contributors)
+
+	establishments, err := ix.GetEstablishmentsContext(ctx, "0690000512001")
+	require.NoError(t, err)
+	assert.Len(t, establishments, 2)
+
+	ok, err := ix.CheckRUCContext(ctx, "0999999999001")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = ix.CheckRUCContext(cancelled, "0690000512001")
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestIndex_InvalidFile(t *testing.T) {
+	ix, err := Open(t.TempDir())
+	require.NoError(t, err)
+	defer ix.Close()
+
+	_, err = ix.Import(writeFile(t, t.TempDir(), "otro.csv", "CODIGO|NOMBRE\n1|A\n"))
+	assert.ErrorIs(t, err, ErrMissingRUC)
+
+	_, err = ix.Import(writeFile(t, t.TempDir(), "vacio.csv", ""))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+
+	_, err = ix.Import(writeFile(t, t.TempDir(), "roto.zip", "no es zip"))
+	assert.ErrorIs(t, err, ErrInvalidFile)
+
+	assert.Equal(t, 0, ix.Len())
+}
+
+func TestOpen_CorruptIndex(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, metaFile, "{}")
+	writeFile(t, dir, dataFile, "")
+	writeFile(t, dir, keysFile, "123")
+
+	_, err := Open(dir)
+	assert.ErrorIs(t, err, ErrCorruptIndex)
+}
@@ -0,0 +1,133 @@
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/asn1"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/pinzlab/sricore/id"
+)
+
+// Authority representa una entidad de certificación acreditada en Ecuador.
+type Authority struct {
+	// Name: Nombre con el que se identifica a la entidad de certificación.
+	Name string
+
+	// Keywords: Palabras de la organización (O) o del nombre común (CN) del emisor que
+	// identifican a la entidad.
+	Keywords []string
+
+	// DNIOIDs: Extensiones en las que la entidad registra la cédula del titular.
+	DNIOIDs []asn1.ObjectIdentifier
+
+	// RUCOIDs: Extensiones en las que la entidad registra el RUC del titular.
+	RUCOIDs []asn1.ObjectIdentifier
+}
+
+// Authorities contiene las entidades de certificación ecuatorianas conocidas, con las
+// extensiones de la identificación del titular definidas en sus políticas de
+// certificación.
+var Authorities = []Authority{
+	{
+		Name:     "Banco Central del Ecuador",
+		Keywords: []string{"BANCO CENTRAL DEL ECUADOR"},
+		DNIOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 37947, 3, 1}},
+		RUCOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 37947, 3, 11}},
+	},
+	{
+		Name:     "Security Data",
+		Keywords: []string{"SECURITY DATA"},
+		DNIOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 37746, 3, 1}},
+		RUCOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 37746, 3, 11}},
+	},
+	{
+		Name:     "ANF AC Ecuador",
+		Keywords: []string{"ANF AC", "ANF AUTORIDAD DE CERTIFICACION ECUADOR"},
+		DNIOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 37442, 3, 1}, {1, 3, 6, 1, 4, 1, 18332, 3, 1}},
+		RUCOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 37442, 3, 11}, {1, 3, 6, 1, 4, 1, 18332, 3, 11}},
+	},
+	{
+		Name:     "Consejo de la Judicatura",
+		Keywords: []string{"CONSEJO DE LA JUDICATURA"},
+		DNIOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 43745, 1, 3, 1}},
+		RUCOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 43745, 1, 3, 11}},
+	},
+	{
+		Name:     "Uanataca Ecuador",
+		Keywords: []string{"UANATACA"},
+		DNIOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 47286, 102, 3, 1}},
+		RUCOIDs:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 4, 1, 47286, 102, 3, 11}},
+	},
+}
+
+// findAuthority busca la entidad de certificación que emitió el certificado por la
+// organización y el nombre común del emisor.
+func findAuthority(c *x509.Certificate) *Authority {
+	names := append(append([]string{}, c.Issuer.Organization...), c.Issuer.CommonName)
+
+	for i := range Authorities {
+		for _, keyword := range Authorities[i].Keywords {
+			for _, name := range names {
+				if containsWords(name, keyword) {
+					return &Authorities[i]
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// containsWords indica si value contiene las palabras de keyword completas y en orden,
+// sin distinguir mayúsculas ni signos de puntuación.
+func containsWords(value, keyword string) bool {
+	return strings.Contains(" "+words(value)+" ", " "+words(keyword)+" ")
+}
+
+// words normaliza un texto a sus palabras en mayúsculas separadas por un espacio.
+func words(value string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToUpper(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// extensionHolder obtiene la cédula y el RUC del titular de las extensiones que la
+// entidad emisora define para ellos. Los valores que no son una cédula o un RUC válidos
+// se descartan.
+func extensionHolder(c *x509.Certificate, authority *Authority) (dni, ruc string) {
+	if authority == nil {
+		return "", ""
+	}
+
+	for _, ext := range c.Extensions {
+		value := extensionText(ext.Value)
+		switch {
+		case dni == "" && hasOID(authority.DNIOIDs, ext.Id) && id.IsDNI(value) == nil:
+			dni = value
+		case ruc == "" && hasOID(authority.RUCOIDs, ext.Id) && id.IsRUC(value) == nil:
+			ruc = value
+		}
+	}
+
+	return dni, ruc
+}
+
+// hasOID indica si el identificador está en la lista.
+func hasOID(oids []asn1.ObjectIdentifier, oid asn1.ObjectIdentifier) bool {
+	return slices.ContainsFunc(oids, oid.Equal)
+}
+
+// extensionText devuelve el contenido textual de una extensión.
+//
+// Las entidades codifican el valor como una cadena ASN.1 (UTF8String, PrintableString
+// o IA5String); algunas versiones antiguas guardan el texto sin codificar.
+func extensionText(value []byte) string {
+	var raw asn1.RawValue
+	if rest, err := asn1.Unmarshal(value, &raw); err == nil && len(rest) == 0 && raw.Class == asn1.ClassUniversal {
+		return strings.TrimSpace(string(raw.Bytes))
+	}
+
+	return strings.TrimSpace(string(value))
+}
@@ -0,0 +1,74 @@
+package cert
+
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindAuthority(t *testing.T) {
+	tests := []struct {
+		issuer pkix.Name
+		name   string
+	}{
+		{issuer: pkix.Name{CommonName: "AC BANCO CENTRAL DEL ECUADOR", Organization: []string{"BANCO CENTRAL DEL ECUADOR"}}, name: "Banco Central del Ecuador"},
+		{issuer: pkix.Name{CommonName: "AUTORIDAD DE CERTIFICACION SUBORDINADA 1 SECURITY DATA", Organization: []string{"SECURITY DATA S.A. 1"}}, name: "Security Data"},
+		{issuer: pkix.Name{CommonName: "ANF AC Ecuador 2020"}, name: "ANF AC Ecuador"},
+		{issuer: pkix.Name{Organization: []string{"Uanataca Ecuador S.A."}}, name: "Uanataca Ecuador"},
+		// Keywords must match whole words of the issuer O or CN.
+		{issuer: pkix.Name{CommonName: "TRANSFER AC ROOT", Organization: []string{"ANFORA CA"}}},
+		{issuer: pkix.Name{CommonName: "AC PRUEBAS", OrganizationalUnit: []string{"ANF AC"}}},
+	}
+
+	for _, test := range tests {
+		authority := findAuthority(&x509.Certificate{Issuer: test.issuer})
+		if test.name == "" {
+			assert.Nil(t, authority, test.issuer.String())
+			continue
+		}
+
+		if assert.NotNil(t, authority, test.issuer.String()) {
+			assert.Equal(t, test.name, authority.Name)
+		}
+	}
+}
+
+func TestExtensionHolder(t *testing.T) {
+	text := func(value string) []byte {
+		data, _ := asn1.Marshal(value)
+		return data
+	}
+
+	bce := findAuthority(&x509.Certificate{Issuer: pkix.Name{Organization: []string{"BANCO CENTRAL DEL ECUADOR"}}})
+	require.NotNil(t, bce)
+
+	c := &x509.Certificate{Extensions: []pkix.Extension{
+		// Unrelated private extensions holding a valid cédula or RUC are ignored.
+		{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 3, 1}, Value: text("1710034065")},
+		{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 37947, 3, 8}, Value: text("0991234567")},
+		{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 37746, 3, 11}, Value: text("1790016919001")},
+		{Id: asn1.ObjectIdentifier{2, 5, 4, 5}, Value: text("1710034065")},
+		{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 37947, 3, 1}, Value: text("0601234560")},
+		{Id: asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 37947, 3, 11}, Value: []byte("0690000512001")},
+	}}
+
+	dni, ruc := extensionHolder(c, bce)
+	assert.Equal(t, "0601234560", dni)
+	assert.Equal(t, "0690000512001", ruc)
+
+	// Without a known issuing authority no extension is trusted.
+	dni, ruc = extensionHolder(c, nil)
+	assert.Empty(t, dni)
+	assert.Empty(t, ruc)
+}
+
+func TestAuthorities_OIDs(t *testing.T) {
+	for _, authority := range Authorities {
+		assert.NotEmpty(t, authority.DNIOIDs, authority.Name)
+		assert.NotEmpty(t, authority.RUCOIDs, authority.Name)
+	}
+}
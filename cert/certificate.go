@@ -0,0 +1,186 @@
+package cert
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// Certificate contiene un certificado de firma electrónica junto con su clave privada
+// (si está disponible) y los certificados intermedios que lo acompañan.
+type Certificate struct {
+	// Leaf: Certificado del titular usado para firmar.
+	Leaf *x509.Certificate
+
+	// Chain: Certificados intermedios o raíz incluidos en el archivo.
+	Chain []*x509.Certificate
+
+	// PrivateKey: Clave privada asociada al certificado; nil si el archivo no la incluye.
+	PrivateKey crypto.Signer
+}
+
+// LoadFile lee un certificado desde disco en formato PKCS#12 (.p12, .pfx) o PEM.
+func LoadFile(path, password string) (*Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Load(data, password)
+}
+
+// Load detecta el formato del contenido (PEM o PKCS#12) y carga el certificado.
+func Load(data []byte, password string) (*Certificate, error) {
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		return LoadPEM(data)
+	}
+
+	return LoadPKCS12(data, password)
+}
+
+// LoadPKCS12 carga un certificado desde un archivo PKCS#12 protegido con contraseña.
+//
+// Los archivos emitidos por algunas entidades ecuatorianas incluyen varios certificados
+// y claves (firma y cifrado); en ese caso se elige el certificado de firma cuya clave
+// pública corresponde a una de las claves privadas del archivo.
+func LoadPKCS12(data []byte, password string) (*Certificate, error) {
+	key, leaf, chain, err := pkcs12.DecodeChain(data, password)
+	if err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, ErrNoPrivateKey
+		}
+
+		return &Certificate{Leaf: leaf, Chain: chain, PrivateKey: signer}, nil
+	}
+
+	if errors.Is(err, pkcs12.ErrIncorrectPassword) {
+		return nil, ErrInvalidPassword
+	}
+
+	blocks, perr := pkcs12.ToPEM(data, password)
+	if perr != nil {
+		if errors.Is(perr, pkcs12.ErrIncorrectPassword) {
+			return nil, ErrInvalidPassword
+		}
+		return nil, ErrInvalidCertificate
+	}
+
+	return fromBlocks(blocks)
+}
+
+// LoadPEM carga un certificado desde contenido PEM. La clave privada es opcional.
+func LoadPEM(data []byte) (*Certificate, error) {
+	var blocks []*pem.Block
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, block)
+	}
+
+	if len(blocks) == 0 {
+		return nil, ErrInvalidCertificate
+	}
+
+	return fromBlocks(blocks)
+}
+
+// fromBlocks arma un Certificate a partir de bloques PEM con certificados y claves.
+func fromBlocks(blocks []*pem.Block) (*Certificate, error) {
+	var certs []*x509.Certificate
+	var keys []crypto.Signer
+
+	for _, block := range blocks {
+		switch block.Type {
+		case "CERTIFICATE":
+			c, err := x509.ParseCertificate(block.Bytes)
+			if err != nil {
+				return nil, ErrInvalidCertificate
+			}
+			certs = append(certs, c)
+
+		case "PRIVATE KEY", "RSA PRIVATE KEY", "EC PRIVATE KEY":
+			key, err := parsePrivateKey(block.Bytes)
+			if err != nil {
+				return nil, ErrInvalidCertificate
+			}
+			keys = append(keys, key)
+		}
+	}
+
+	if len(certs) == 0 {
+		return nil, ErrNoCertificate
+	}
+
+	result := &Certificate{}
+
+	// Se prefiere el certificado de firma cuya clave privada está en el archivo
+	for _, c := range certs {
+		if c.IsCA {
+			continue
+		}
+		for _, key := range keys {
+			if publicKeyEqual(c.PublicKey, key.Public()) && (result.PrivateKey == nil || isSigning(c)) {
+				result.Leaf, result.PrivateKey = c, key
+			}
+		}
+	}
+
+	// Sin una clave que coincida, se toma el primer certificado de entidad final
+	if result.Leaf == nil {
+		for _, c := range certs {
+			if !c.IsCA {
+				result.Leaf = c
+				break
+			}
+		}
+	}
+
+	if result.Leaf == nil {
+		return nil, ErrNoCertificate
+	}
+
+	for _, c := range certs {
+		if c.IsCA {
+			result.Chain = append(result.Chain, c)
+		}
+	}
+
+	return result, nil
+}
+
+// parsePrivateKey interpreta una clave privada en formato PKCS#8, PKCS#1 o SEC 1.
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, ErrNoPrivateKey
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+
+	return x509.ParseECPrivateKey(der)
+}
+
+// publicKeyEqual compara dos claves públicas.
+func publicKeyEqual(a, b crypto.PublicKey) bool {
+	key, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && key.Equal(b)
+}
+
+// isSigning indica si el certificado está destinado a firma digital o no repudio.
+func isSigning(c *x509.Certificate) bool {
+	return c.KeyUsage&(x509.KeyUsageDigitalSignature|x509.KeyUsageContentCommitment) != 0
+}
@@ -0,0 +1,144 @@
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// testKey es la clave RSA compartida por los certificados de prueba.
+var testKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+
+// testHolder describe el titular de un certificado de prueba.
+type testHolder struct {
+	dni       string
+	ruc       string
+	serial    string
+	notBefore time.Time
+	notAfter  time.Time
+}
+
+// newTestCA crea una entidad de certificación autofirmada con la organización indicada.
+func newTestCA(t *testing.T, organization string) *x509.Certificate {
+	t.Helper()
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "AC " + organization, Organization: []string{organization}},
+		NotBefore:             time.Now().Add(-365 * 24 * time.Hour),
+		NotAfter:              time.Now().Add(10 * 365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &testKey.PublicKey, testKey)
+	require.NoError(t, err)
+
+	ca, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return ca
+}
+
+// Extensiones privadas de prueba con la cédula y el RUC del titular, usadas cuando la
+// entidad de prueba no es una entidad conocida.
+var (
+	testDNIOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 3, 1}
+	testRUCOID = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 99999, 3, 11}
+)
+
+// newTestLeaf crea un certificado de firma emitido por ca con la identificación del
+// titular en las extensiones privadas de la entidad.
+func newTestLeaf(t *testing.T, ca *x509.Certificate, holder testHolder) *x509.Certificate {
+	t.Helper()
+
+	if holder.notBefore.IsZero() {
+		holder.notBefore = time.Now().Add(-24 * time.Hour)
+	}
+	if holder.notAfter.IsZero() {
+		holder.notAfter = time.Now().Add(365 * 24 * time.Hour)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "JUAN PEREZ", SerialNumber: holder.serial},
+		NotBefore:    holder.notBefore,
+		NotAfter:     holder.notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+	}
+
+	dniOID, rucOID := testDNIOID, testRUCOID
+	if authority := findAuthority(&x509.Certificate{Issuer: ca.Subject}); authority != nil {
+		dniOID, rucOID = authority.DNIOIDs[0], authority.RUCOIDs[0]
+	}
+
+	if holder.dni != "" {
+		value, _ := asn1.Marshal(holder.dni)
+		template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{Id: dniOID, Value: value})
+	}
+	if holder.ruc != "" {
+		value, _ := asn1.Marshal(holder.ruc)
+		template.ExtraExtensions = append(template.ExtraExtensions, pkix.Extension{Id: rucOID, Value: value})
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &testKey.PublicKey, testKey)
+	require.NoError(t, err)
+
+	leaf, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return leaf
+}
+
+func TestLoadPKCS12(t *testing.T) {
+	ca := newTestCA(t, "BANCO CENTRAL DEL ECUADOR")
+	leaf := newTestLeaf(t, ca, testHolder{dni: "0601234560"})
+
+	data, err := pkcs12.Modern.Encode(testKey, leaf, []*x509.Certificate{ca}, "secreto")
+	require.NoError(t, err)
+
+	c, err := Load(data, "secreto")
+	require.NoError(t, err)
+	assert.Equal(t, leaf.Raw, c.Leaf.Raw)
+	assert.Len(t, c.Chain, 1)
+	assert.NotNil(t, c.PrivateKey)
+
+	_, err = Load(data, "incorrecta")
+	assert.ErrorIs(t, err, ErrInvalidPassword)
+}
+
+func TestLoadPEM(t *testing.T) {
+	ca := newTestCA(t, "SECURITY DATA S.A.")
+	leaf := newTestLeaf(t, ca, testHolder{ruc: "0601234560001"})
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(testKey)
+	require.NoError(t, err)
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})...)
+	data = append(data, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})...)
+
+	c, err := Load(data, "")
+	require.NoError(t, err)
+	assert.Equal(t, leaf.Raw, c.Leaf.Raw)
+	assert.Equal(t, []*x509.Certificate{ca}, c.Chain)
+	assert.NotNil(t, c.PrivateKey)
+
+	c, err = LoadPEM(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}))
+	require.NoError(t, err)
+	assert.Nil(t, c.PrivateKey)
+
+	_, err = LoadPEM([]byte("no es un certificado"))
+	assert.ErrorIs(t, err, ErrInvalidCertificate)
+}
@@ -0,0 +1,259 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RevocationReason es el motivo de revocación definido en RFC 5280.
+type RevocationReason int
+
+const (
+	ReasonUnspecified          RevocationReason = 0
+	ReasonKeyCompromise        RevocationReason = 1
+	ReasonCACompromise         RevocationReason = 2
+	ReasonAffiliationChanged   RevocationReason = 3
+	ReasonSuperseded           RevocationReason = 4
+	ReasonCessationOfOperation RevocationReason = 5
+	ReasonCertificateHold      RevocationReason = 6
+	ReasonRemoveFromCRL        RevocationReason = 8
+	ReasonPrivilegeWithdrawn   RevocationReason = 9
+	ReasonAACompromise         RevocationReason = 10
+)
+
+// reasonNames contiene la descripción en español de cada motivo de revocación.
+var reasonNames = map[RevocationReason]string{
+	ReasonUnspecified:          "sin especificar",
+	ReasonKeyCompromise:        "clave comprometida",
+	ReasonCACompromise:         "entidad de certificación comprometida",
+	ReasonAffiliationChanged:   "cambio de afiliación",
+	ReasonSuperseded:           "reemplazado",
+	ReasonCessationOfOperation: "cese de operaciones",
+	ReasonCertificateHold:      "suspendido",
+	ReasonRemoveFromCRL:        "retirado de la lista",
+	ReasonPrivilegeWithdrawn:   "privilegios retirados",
+	ReasonAACompromise:         "autoridad de atributos comprometida",
+}
+
+// String devuelve la descripción del motivo de revocación.
+func (r RevocationReason) String() string {
+	if name, ok := reasonNames[r]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("motivo %d", int(r))
+}
+
+// Revocation describe la revocación de un certificado registrada en una CRL.
+//
+// Implementa error y coincide con ErrCertificateRevoked mediante errors.Is, de modo que
+// puede recuperarse con errors.As al verificar una firma.
+type Revocation struct {
+	// SerialNumber: Número de serie del certificado revocado.
+	SerialNumber *big.Int
+
+	// RevokedAt: Fecha de revocación.
+	RevokedAt time.Time
+
+	// Reason: Motivo de revocación.
+	Reason RevocationReason
+
+	// Issuer: Emisor de la CRL que registra la revocación.
+	Issuer string
+
+	// CRLUpdate: Fecha de publicación de la CRL consultada.
+	CRLUpdate time.Time
+}
+
+// Error implementa la interfaz error.
+func (r *Revocation) Error() string {
+	return fmt.Sprintf("%s: %s el %s", ErrCertificateRevoked, r.Reason, r.RevokedAt.Format(time.RFC3339))
+}
+
+// Unwrap permite comparar la revocación con ErrCertificateRevoked.
+func (r *Revocation) Unwrap() error {
+	return ErrCertificateRevoked
+}
+
+// CRLStore es un conjunto de listas de revocación descargadas previamente, que permite
+// comprobar la revocación de certificados sin conexión.
+type CRLStore struct {
+	mu    sync.RWMutex
+	lists []*x509.RevocationList
+}
+
+// NewCRLStore crea un conjunto de listas de revocación vacío.
+func NewCRLStore() *CRLStore {
+	return &CRLStore{}
+}
+
+// LoadDir agrega las listas de revocación (.crl, .pem) de un directorio.
+func (s *CRLStore) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".crl", ".pem":
+		default:
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		if err := s.Add(data); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Add agrega una lista de revocación en formato DER o PEM.
+func (s *CRLStore) Add(data []byte) error {
+	var ders [][]byte
+
+	if bytes.Contains(data, []byte("-----BEGIN")) {
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+			if block.Type == "X509 CRL" {
+				ders = append(ders, block.Bytes)
+			}
+		}
+	} else {
+		ders = append(ders, data)
+	}
+
+	if len(ders) == 0 {
+		return ErrInvalidCRL
+	}
+
+	for _, der := range ders {
+		list, err := x509.ParseRevocationList(der)
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidCRL, err)
+		}
+
+		s.mu.Lock()
+		s.lists = append(s.lists, list)
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Check busca el certificado en las listas publicadas por su emisor y devuelve la
+// revocación si fue revocado en o antes del instante indicado; nil en caso contrario.
+//
+// Si issuer no es nil se verifica la firma de cada lista con ese certificado; las listas
+// con una firma inválida se descartan. Las revocaciones definitivas se buscan en todas las
+// listas del emisor, pero una suspensión (certificateHold) solo cuenta si sigue en la
+// lista más reciente, que puede haberla levantado omitiendo el certificado o con el
+// motivo removeFromCRL.
+//
+// Retorna ErrCRLNotFound si no hay listas del emisor publicadas después del instante
+// indicado, ya que no permitirían conocer el estado del certificado en ese momento.
+func (s *CRLStore) Check(c, issuer *x509.Certificate, at time.Time) (*Revocation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var latest *x509.RevocationList
+	var revocation *Revocation
+	for _, list := range s.lists {
+		if !bytes.Equal(list.RawIssuer, c.RawIssuer) {
+			continue
+		}
+
+		if issuer != nil {
+			if err := list.CheckSignatureFrom(issuer); err != nil {
+				slog.Warn("skipping CRL with invalid signature", "issuer", list.Issuer.String(), "number", list.Number, "error", err)
+				continue
+			}
+		}
+
+		if latest == nil || newerCRL(list, latest) {
+			latest = list
+		}
+
+		entry := revokedEntry(list, c, at)
+		if revocation == nil && entry != nil && !held(entry) {
+			revocation = newRevocation(list, entry)
+		}
+	}
+
+	if latest == nil || latest.ThisUpdate.Before(at) {
+		return nil, ErrCRLNotFound
+	}
+
+	if revocation != nil {
+		return revocation, nil
+	}
+
+	if entry := revokedEntry(latest, c, at); entry != nil && RevocationReason(entry.ReasonCode) == ReasonCertificateHold {
+		return newRevocation(latest, entry), nil
+	}
+
+	return nil, nil
+}
+
+// newerCRL indica si la lista a es más reciente que b, por su fecha de publicación y, a
+// igual fecha, por su número.
+func newerCRL(a, b *x509.RevocationList) bool {
+	if !a.ThisUpdate.Equal(b.ThisUpdate) {
+		return a.ThisUpdate.After(b.ThisUpdate)
+	}
+
+	return a.Number != nil && (b.Number == nil || a.Number.Cmp(b.Number) > 0)
+}
+
+// revokedEntry devuelve la entrada del certificado en la lista si fue revocado en o antes
+// del instante indicado.
+func revokedEntry(list *x509.RevocationList, c *x509.Certificate, at time.Time) *x509.RevocationListEntry {
+	for i, entry := range list.RevokedCertificateEntries {
+		if entry.SerialNumber.Cmp(c.SerialNumber) == 0 && !entry.RevocationTime.After(at) {
+			return &list.RevokedCertificateEntries[i]
+		}
+	}
+
+	return nil
+}
+
+// held indica si la entrada es una suspensión o el levantamiento de una suspensión, que
+// dependen de la lista más reciente.
+func held(entry *x509.RevocationListEntry) bool {
+	switch RevocationReason(entry.ReasonCode) {
+	case ReasonCertificateHold, ReasonRemoveFromCRL:
+		return true
+	default:
+		return false
+	}
+}
+
+// newRevocation describe la revocación registrada en una entrada de la lista.
+func newRevocation(list *x509.RevocationList, entry *x509.RevocationListEntry) *Revocation {
+	return &Revocation{
+		SerialNumber: entry.SerialNumber,
+		RevokedAt:    entry.RevocationTime,
+		Reason:       RevocationReason(entry.ReasonCode),
+		Issuer:       list.Issuer.String(),
+		CRLUpdate:    list.ThisUpdate,
+	}
+}
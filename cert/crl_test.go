@@ -0,0 +1,159 @@
+package cert
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCRL crea una lista de revocación firmada por ca con las entradas indicadas.
+func newTestCRL(t *testing.T, ca *x509.Certificate, thisUpdate time.Time, entries ...x509.RevocationListEntry) []byte {
+	t.Helper()
+
+	der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:                    big.NewInt(thisUpdate.Unix()),
+		ThisUpdate:                thisUpdate,
+		NextUpdate:                thisUpdate.Add(24 * time.Hour),
+		RevokedCertificateEntries: entries,
+	}, ca, testKey)
+	require.NoError(t, err)
+
+	return der
+}
+
+func TestCRLStore_Check(t *testing.T) {
+	ca := newTestCA(t, "BANCO CENTRAL DEL ECUADOR")
+	leaf := newTestLeaf(t, ca, testHolder{dni: "0601234560"})
+	other := newTestLeaf(t, ca, testHolder{dni: "0601234578"})
+	now := time.Now()
+	revokedAt := now.Add(-time.Hour)
+
+	dir := t.TempDir()
+	der := newTestCRL(t, ca, now, x509.RevocationListEntry{
+		SerialNumber:   leaf.SerialNumber,
+		RevocationTime: revokedAt,
+		ReasonCode:     int(ReasonKeyCompromise),
+	})
+	data := pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bce.crl"), data, 0o600))
+
+	store := NewCRLStore()
+	require.NoError(t, store.LoadDir(dir))
+
+	revocation, err := store.Check(leaf, ca, now.Add(-time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, revocation)
+	assert.Equal(t, ReasonKeyCompromise, revocation.Reason)
+	assert.Equal(t, revokedAt.Unix(), revocation.RevokedAt.Unix())
+	assert.True(t, errors.Is(revocation, ErrCertificateRevoked))
+
+	// Signed before the revocation date
+	revocation, err = store.Check(leaf, ca, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+
+	revocation, err = store.Check(other, ca, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+
+	// The CRL was published before the instant being checked
+	_, err = store.Check(other, ca, now.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrCRLNotFound)
+}
+
+func TestCRLStore_CheckHold(t *testing.T) {
+	ca := newTestCA(t, "BANCO CENTRAL DEL ECUADOR")
+	leaf := newTestLeaf(t, ca, testHolder{dni: "0601234560"})
+	now := time.Now()
+	hold := x509.RevocationListEntry{
+		SerialNumber:   leaf.SerialNumber,
+		RevocationTime: now.Add(-2 * time.Hour),
+		ReasonCode:     int(ReasonCertificateHold),
+	}
+
+	store := NewCRLStore()
+	require.NoError(t, store.Add(newTestCRL(t, ca, now.Add(-time.Hour), hold)))
+
+	revocation, err := store.Check(leaf, ca, now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, revocation)
+	assert.Equal(t, ReasonCertificateHold, revocation.Reason)
+
+	// A later CRL lifts the hold by omitting the certificate.
+	require.NoError(t, store.Add(newTestCRL(t, ca, now)))
+	revocation, err = store.Check(leaf, ca, now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+
+	// An even later CRL lifts it with removeFromCRL.
+	store = NewCRLStore()
+	require.NoError(t, store.Add(newTestCRL(t, ca, now.Add(-time.Hour), hold)))
+	require.NoError(t, store.Add(newTestCRL(t, ca, now, x509.RevocationListEntry{
+		SerialNumber:   leaf.SerialNumber,
+		RevocationTime: now.Add(-30 * time.Minute),
+		ReasonCode:     int(ReasonRemoveFromCRL),
+	})))
+	revocation, err = store.Check(leaf, ca, now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+
+	// A permanent revocation found in an older CRL is not lifted.
+	store = NewCRLStore()
+	require.NoError(t, store.Add(newTestCRL(t, ca, now.Add(-time.Hour), x509.RevocationListEntry{
+		SerialNumber:   leaf.SerialNumber,
+		RevocationTime: now.Add(-2 * time.Hour),
+		ReasonCode:     int(ReasonKeyCompromise),
+	})))
+	require.NoError(t, store.Add(newTestCRL(t, ca, now)))
+	revocation, err = store.Check(leaf, ca, now.Add(-90*time.Minute))
+	require.NoError(t, err)
+	require.NotNil(t, revocation)
+	assert.Equal(t, ReasonKeyCompromise, revocation.Reason)
+}
+
+func TestCRLStore_CheckBadSignature(t *testing.T) {
+	ca := newTestCA(t, "BANCO CENTRAL DEL ECUADOR")
+	leaf := newTestLeaf(t, ca, testHolder{dni: "0601234560"})
+	now := time.Now()
+
+	// A CRL with the same issuer name signed by another key.
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	forged, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+		Number:     big.NewInt(now.Unix() + 1),
+		ThisUpdate: now.Add(time.Minute),
+		NextUpdate: now.Add(24 * time.Hour),
+		RevokedCertificateEntries: []x509.RevocationListEntry{
+			{SerialNumber: leaf.SerialNumber, RevocationTime: now.Add(-time.Hour), ReasonCode: int(ReasonKeyCompromise)},
+		},
+	}, &x509.Certificate{Subject: ca.Subject, SubjectKeyId: ca.SubjectKeyId, KeyUsage: x509.KeyUsageCRLSign}, otherKey)
+	require.NoError(t, err)
+
+	store := NewCRLStore()
+	require.NoError(t, store.Add(forged))
+	require.NoError(t, store.Add(newTestCRL(t, ca, now)))
+
+	revocation, err := store.Check(leaf, ca, now.Add(-time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, revocation)
+
+	// Only the forged CRL: there is no valid list to check against.
+	store = NewCRLStore()
+	require.NoError(t, store.Add(forged))
+	_, err = store.Check(leaf, ca, now.Add(-time.Minute))
+	assert.ErrorIs(t, err, ErrCRLNotFound)
+}
+
+func TestCRLStore_Add(t *testing.T) {
+	assert.ErrorIs(t, NewCRLStore().Add([]byte("no es una CRL")), ErrInvalidCRL)
+}
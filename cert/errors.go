@@ -0,0 +1,27 @@
+package cert
+
+import "errors"
+
+var (
+	ErrInvalidCertificate  = errors.New("No se pudo leer el certificado")
+	ErrInvalidPassword     = errors.New("La contraseña del certificado es incorrecta")
+	ErrNoCertificate       = errors.New("El archivo no contiene un certificado de firma")
+	ErrNoPrivateKey        = errors.New("El archivo no contiene una clave privada para el certificado")
+	ErrUntrustedChain      = errors.New("El certificado no se encadena a una entidad de certificación de confianza")
+	ErrNoTrustedRoots      = errors.New("El almacén de confianza no contiene certificados raíz")
+	ErrUnpinnedCertificate = errors.New("El certificado incorporado no coincide con ninguna huella registrada")
+	ErrInvalidCRL          = errors.New("No se pudo leer la lista de revocación")
+	ErrCRLNotFound         = errors.New("No hay una lista de revocación vigente del emisor del certificado")
+	ErrCertificateRevoked  = errors.New("El certificado fue revocado")
+)
+
+// Advertencias generadas al inspeccionar un certificado.
+var (
+	ErrCertificateExpired  = errors.New("El certificado está caducado")
+	ErrCertificateNotYet   = errors.New("El certificado aún no es válido")
+	ErrCertificateExpiring = errors.New("El certificado caducará pronto")
+	ErrHolderNotFound      = errors.New("No se encontró la cédula ni el RUC del titular en el certificado")
+	ErrHolderInvalid       = errors.New("La identificación del titular del certificado no es válida")
+	ErrHolderMismatch      = errors.New("El titular del certificado no corresponde al RUC del emisor")
+	ErrUnknownIssuer       = errors.New("El certificado no fue emitido por una entidad de certificación ecuatoriana conocida")
+)
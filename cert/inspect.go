@@ -0,0 +1,138 @@
+package cert
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pinzlab/sricore/id"
+)
+
+// ExpiringThreshold es la antelación con la que se advierte que un certificado caducará.
+const ExpiringThreshold = 30 * 24 * time.Hour
+
+// Info resume los datos relevantes de un certificado de firma electrónica.
+type Info struct {
+	// Subject: Nombre distinguido del titular.
+	Subject string
+
+	// Issuer: Nombre distinguido de la entidad que emitió el certificado.
+	Issuer string
+
+	// Authority: Nombre de la entidad de certificación ecuatoriana; vacío si no se reconoce.
+	Authority string
+
+	// SerialNumber: Número de serie del certificado en hexadecimal.
+	SerialNumber string
+
+	// NotBefore: Inicio del periodo de validez.
+	NotBefore time.Time
+
+	// NotAfter: Fin del periodo de validez.
+	NotAfter time.Time
+
+	// DNI: Cédula del titular registrada por la entidad de certificación.
+	DNI string
+
+	// RUC: RUC del titular registrado por la entidad de certificación.
+	RUC string
+
+	// Warnings: Problemas encontrados durante la inspección.
+	Warnings []error
+}
+
+// Inspect analiza el certificado y reporta su periodo de validez, la entidad emisora
+// y la identificación del titular.
+//
+// Si emitterRUC no está vacío, se advierte cuando el titular no corresponde a ese RUC.
+func (c *Certificate) Inspect(emitterRUC string) *Info {
+	return c.inspect(emitterRUC, time.Now())
+}
+
+// inspect realiza la inspección tomando como referencia el instante indicado.
+func (c *Certificate) inspect(emitterRUC string, now time.Time) *Info {
+	leaf := c.Leaf
+	info := &Info{
+		Subject:      leaf.Subject.String(),
+		Issuer:       leaf.Issuer.String(),
+		SerialNumber: fmt.Sprintf("%X", leaf.SerialNumber),
+		NotBefore:    leaf.NotBefore,
+		NotAfter:     leaf.NotAfter,
+	}
+
+	authority := findAuthority(leaf)
+	if authority != nil {
+		info.Authority = authority.Name
+	} else {
+		info.Warnings = append(info.Warnings, ErrUnknownIssuer)
+	}
+
+	info.DNI, info.RUC = extensionHolder(leaf, authority)
+
+	if info.DNI == "" && info.RUC == "" {
+		info.DNI, info.RUC = subjectHolder(leaf.Subject.SerialNumber)
+	}
+
+	switch {
+	case now.Before(leaf.NotBefore):
+		info.Warnings = append(info.Warnings, ErrCertificateNotYet)
+	case now.After(leaf.NotAfter):
+		info.Warnings = append(info.Warnings, ErrCertificateExpired)
+	case leaf.NotAfter.Sub(now) < ExpiringThreshold:
+		info.Warnings = append(info.Warnings, ErrCertificateExpiring)
+	}
+
+	if info.DNI == "" && info.RUC == "" {
+		info.Warnings = append(info.Warnings, ErrHolderNotFound)
+		return info
+	}
+
+	if info.DNI != "" {
+		if err := id.IsDNI(info.DNI); err != nil {
+			info.Warnings = append(info.Warnings, fmt.Errorf("%w: %s: %v", ErrHolderInvalid, info.DNI, err))
+		}
+	}
+
+	if info.RUC != "" {
+		if err := id.IsRUC(info.RUC); err != nil {
+			info.Warnings = append(info.Warnings, fmt.Errorf("%w: %s: %v", ErrHolderInvalid, info.RUC, err))
+		}
+	}
+
+	if emitterRUC != "" && !info.Matches(emitterRUC) {
+		info.Warnings = append(info.Warnings, fmt.Errorf("%w: %s", ErrHolderMismatch, emitterRUC))
+	}
+
+	return info
+}
+
+// Matches indica si el titular del certificado corresponde al RUC indicado, ya sea
+// porque el certificado registra ese RUC o porque el RUC es el de persona natural
+// derivado de la cédula del titular.
+func (info *Info) Matches(ruc string) bool {
+	if info.RUC != "" && info.RUC == ruc {
+		return true
+	}
+
+	return info.DNI != "" && info.DNI+"001" == ruc
+}
+
+// IsValidAt indica si el instante indicado está dentro del periodo de validez.
+func (info *Info) IsValidAt(t time.Time) bool {
+	return !t.Before(info.NotBefore) && !t.After(info.NotAfter)
+}
+
+// subjectHolder obtiene la cédula o el RUC del atributo serialNumber del sujeto,
+// usado por entidades que no registran extensiones propias.
+func subjectHolder(serial string) (dni, ruc string) {
+	digits := regexp.MustCompile(`\d{13}|\d{10}`).FindString(serial)
+
+	switch len(digits) {
+	case 10:
+		return digits, ""
+	case 13:
+		return "", digits
+	}
+
+	return "", ""
+}
@@ -0,0 +1,68 @@
+package cert
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInspect(t *testing.T) {
+	ca := newTestCA(t, "BANCO CENTRAL DEL ECUADOR")
+	c := &Certificate{Leaf: newTestLeaf(t, ca, testHolder{dni: "0601234560", ruc: "0690000512001"})}
+
+	info := c.Inspect("0690000512001")
+
+	assert.Equal(t, "Banco Central del Ecuador", info.Authority)
+	assert.Equal(t, "0601234560", info.DNI)
+	assert.Equal(t, "0690000512001", info.RUC)
+	assert.Empty(t, info.Warnings)
+	assert.True(t, info.IsValidAt(time.Now()))
+}
+
+func TestInspect_Warnings(t *testing.T) {
+	ca := newTestCA(t, "BANCO CENTRAL DEL ECUADOR")
+	unknown := newTestCA(t, "AUTOFIRMADO")
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		ca      string
+		holder  testHolder
+		emitter string
+		err     error
+	}{
+		{name: "expired", holder: testHolder{dni: "0601234560", notBefore: now.Add(-48 * time.Hour), notAfter: now.Add(-time.Hour)}, err: ErrCertificateExpired},
+		{name: "not yet", holder: testHolder{dni: "0601234560", notBefore: now.Add(time.Hour)}, err: ErrCertificateNotYet},
+		{name: "expiring", holder: testHolder{dni: "0601234560", notAfter: now.Add(24 * time.Hour)}, err: ErrCertificateExpiring},
+		{name: "missing holder", holder: testHolder{}, err: ErrHolderNotFound},
+		{name: "invalid dni", holder: testHolder{serial: "0601234561"}, err: ErrHolderInvalid},
+		{name: "mismatch", holder: testHolder{dni: "0601234560"}, emitter: "0690000512001", err: ErrHolderMismatch},
+		{name: "unknown issuer", ca: "unknown", holder: testHolder{serial: "CI 0601234560"}, err: ErrUnknownIssuer},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			issuer := ca
+			if test.ca == "unknown" {
+				issuer = unknown
+			}
+
+			c := &Certificate{Leaf: newTestLeaf(t, issuer, test.holder)}
+			info := c.Inspect(test.emitter)
+
+			assert.Len(t, info.Warnings, 1)
+			assert.ErrorIs(t, info.Warnings[0], test.err)
+		})
+	}
+}
+
+func TestInfo_Matches(t *testing.T) {
+	natural := &Info{DNI: "0601234560"}
+	company := &Info{DNI: "0601234560", RUC: "0690000512001"}
+
+	assert.True(t, natural.Matches("0601234560001"))
+	assert.False(t, natural.Matches("0690000512001"))
+	assert.True(t, company.Matches("0690000512001"))
+	assert.True(t, company.Matches("0601234560001"))
+}
@@ -0,0 +1,219 @@
+package cert
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"embed"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// embeddedRoots contiene los certificados raíz e intermedios de las entidades
+// de certificación ecuatorianas incorporados en la librería.
+//
+//go:embed roots
+var embeddedRoots embed.FS
+
+// TrustStore es un almacén de certificados raíz e intermedios de confianza usado para
+// validar la cadena de certificación de un certificado de firma.
+type TrustStore struct {
+	mu            sync.RWMutex
+	roots         []*x509.Certificate
+	intermediates []*x509.Certificate
+}
+
+// NewTrustStore crea un almacén de confianza vacío.
+func NewTrustStore() *TrustStore {
+	return &TrustStore{}
+}
+
+// pinnedRoots contiene la huella SHA-256 (en hexadecimal, minúsculas) de cada certificado
+// del directorio cert/roots y la entidad a la que pertenece. Un certificado incorporado
+// sin su huella en esta tabla impide cargar el almacén por defecto, de modo que los
+// archivos del directorio no pueden reemplazarse sin actualizar el código.
+var pinnedRoots = map[string]string{}
+
+// defaultTrustStore es el almacén asignado con SetDefaultTrustStore.
+var defaultTrustStore atomic.Pointer[TrustStore]
+
+// DefaultTrustStore devuelve el almacén asignado con SetDefaultTrustStore o, si no se
+// asignó ninguno, crea uno con las raíces e intermedios de las entidades de certificación
+// ecuatorianas incorporados en la librería (directorio cert/roots), comprobados contra
+// sus huellas SHA-256. Si no se incorporó ninguna raíz devuelve ErrNoTrustedRoots, para
+// que la verificación de una firma no falle como si la cadena no fuera de confianza.
+func DefaultTrustStore() (*TrustStore, error) {
+	if store := defaultTrustStore.Load(); store != nil {
+		return store, nil
+	}
+
+	return loadTrustStore(embeddedRoots, "roots", pinnedRoots)
+}
+
+// SetDefaultTrustStore reemplaza el almacén devuelto por DefaultTrustStore, y usado por
+// xades.Verify cuando no recibe uno, por ejemplo para agregar raíces actualizadas sin
+// recompilar. Un almacén nil restablece el almacén incorporado.
+func SetDefaultTrustStore(store *TrustStore) {
+	defaultTrustStore.Store(store)
+}
+
+// loadTrustStore crea un almacén de confianza con los certificados de un directorio,
+// comprueba que cada uno tenga su huella en pins y que haya al menos una raíz.
+func loadTrustStore(fsys fs.FS, dir string, pins map[string]string) (*TrustStore, error) {
+	store := NewTrustStore()
+	if err := store.loadFS(fsys, dir); err != nil {
+		return nil, err
+	}
+
+	for _, c := range append(store.Roots(), store.intermediates...) {
+		if _, ok := pins[Fingerprint(c)]; !ok {
+			return nil, fmt.Errorf("%w: %s (%s)", ErrUnpinnedCertificate, c.Subject.CommonName, Fingerprint(c))
+		}
+	}
+
+	if len(store.Roots()) == 0 {
+		return nil, ErrNoTrustedRoots
+	}
+
+	return store, nil
+}
+
+// Fingerprint devuelve la huella SHA-256 del certificado en hexadecimal, en minúsculas.
+func Fingerprint(c *x509.Certificate) string {
+	sum := sha256.Sum256(c.Raw)
+	return hex.EncodeToString(sum[:])
+}
+
+// AddCertificate agrega un certificado al almacén. Los certificados autofirmados se
+// registran como raíces y el resto como intermedios.
+func (s *TrustStore) AddCertificate(c *x509.Certificate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if isSelfSigned(c) {
+		s.roots = append(s.roots, c)
+	} else {
+		s.intermediates = append(s.intermediates, c)
+	}
+}
+
+// AddPEM agrega todos los certificados contenidos en un bloque PEM.
+func (s *TrustStore) AddPEM(data []byte) error {
+	found := false
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return ErrInvalidCertificate
+		}
+
+		s.AddCertificate(c)
+		found = true
+	}
+
+	if !found {
+		return ErrNoCertificate
+	}
+
+	return nil
+}
+
+// LoadDir agrega los certificados PEM (.pem, .crt, .cer) de un directorio, lo que permite
+// actualizar las raíces sin recompilar.
+func (s *TrustStore) LoadDir(dir string) error {
+	return s.loadFS(os.DirFS(dir), ".")
+}
+
+// loadFS agrega los certificados PEM encontrados en el directorio indicado del sistema de archivos.
+func (s *TrustStore) loadFS(fsys fs.FS, dir string) error {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		switch strings.ToLower(path.Ext(entry.Name())) {
+		case ".pem", ".crt", ".cer":
+		default:
+			continue
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return err
+		}
+
+		if err := s.AddPEM(data); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Roots devuelve los certificados raíz registrados.
+func (s *TrustStore) Roots() []*x509.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return append([]*x509.Certificate(nil), s.roots...)
+}
+
+// Verify comprueba que el certificado se encadena a una raíz de confianza en el instante
+// indicado, normalmente la fecha de firma registrada en el comprobante.
+//
+// Los intermedios recibidos se suman a los registrados en el almacén.
+// Retorna las cadenas válidas encontradas, desde el certificado hasta la raíz.
+func (s *TrustStore) Verify(leaf *x509.Certificate, intermediates []*x509.Certificate, at time.Time) ([][]*x509.Certificate, error) {
+	s.mu.RLock()
+	roots := x509.NewCertPool()
+	for _, c := range s.roots {
+		roots.AddCert(c)
+	}
+
+	pool := x509.NewCertPool()
+	for _, c := range s.intermediates {
+		pool.AddCert(c)
+	}
+	s.mu.RUnlock()
+
+	for _, c := range intermediates {
+		if !isSelfSigned(c) {
+			pool.AddCert(c)
+		}
+	}
+
+	chains, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: pool,
+		CurrentTime:   at,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUntrustedChain, err)
+	}
+
+	return chains, nil
+}
+
+// isSelfSigned indica si el certificado está firmado por su propia clave.
+func isSelfSigned(c *x509.Certificate) bool {
+	return bytes.Equal(c.RawSubject, c.RawIssuer) && c.CheckSignatureFrom(c) == nil
+}
@@ -0,0 +1,95 @@
+package cert
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTrustStore(t *testing.T) {
+	store, err := DefaultTrustStore()
+	if err != nil {
+		// Without embedded roots, verification must fail explicitly.
+		assert.ErrorIs(t, err, ErrNoTrustedRoots)
+		return
+	}
+
+	assert.NotEmpty(t, store.Roots())
+}
+
+func TestLoadTrustStore(t *testing.T) {
+	ca := newTestCA(t, "BANCO CENTRAL DEL ECUADOR")
+	leaf := newTestLeaf(t, ca, testHolder{dni: "0601234560"})
+
+	_, err := loadTrustStore(fstest.MapFS{"roots/README.md": {Data: []byte("# Raíces")}}, "roots", nil)
+	assert.ErrorIs(t, err, ErrNoTrustedRoots)
+
+	roots := fstest.MapFS{
+		"roots/bce-raiz.pem": {Data: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})},
+	}
+
+	store, err := loadTrustStore(roots, "roots", map[string]string{Fingerprint(ca): "Banco Central del Ecuador"})
+	require.NoError(t, err)
+	require.Len(t, store.Roots(), 1)
+
+	_, err = store.Verify(leaf, nil, time.Now())
+	assert.NoError(t, err)
+
+	// A root without its pinned fingerprint is rejected.
+	_, err = loadTrustStore(roots, "roots", map[string]string{"00": "Banco Central del Ecuador"})
+	assert.ErrorIs(t, err, ErrUnpinnedCertificate)
+}
+
+func TestSetDefaultTrustStore(t *testing.T) {
+	store := NewTrustStore()
+	store.AddCertificate(newTestCA(t, "SECURITY DATA S.A."))
+
+	SetDefaultTrustStore(store)
+	t.Cleanup(func() { SetDefaultTrustStore(nil) })
+
+	got, err := DefaultTrustStore()
+	require.NoError(t, err)
+	assert.Same(t, store, got)
+}
+
+func TestPinnedRoots(t *testing.T) {
+	// Every embedded certificate must be pinned.
+	_, err := loadTrustStore(embeddedRoots, "roots", pinnedRoots)
+	assert.NotErrorIs(t, err, ErrUnpinnedCertificate)
+}
+
+func TestTrustStore_Verify(t *testing.T) {
+	ca := newTestCA(t, "BANCO CENTRAL DEL ECUADOR")
+	leaf := newTestLeaf(t, ca, testHolder{dni: "0601234560"})
+
+	dir := t.TempDir()
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.Raw})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "bce-raiz.pem"), data, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "LEEME.txt"), []byte("ignorado"), 0o600))
+
+	store := NewTrustStore()
+	require.NoError(t, store.LoadDir(dir))
+	assert.Len(t, store.Roots(), 1)
+
+	chains, err := store.Verify(leaf, nil, time.Now())
+	require.NoError(t, err)
+	assert.Len(t, chains[0], 2)
+
+	_, err = store.Verify(leaf, nil, leaf.NotAfter.Add(time.Hour))
+	assert.ErrorIs(t, err, ErrUntrustedChain)
+
+	_, err = NewTrustStore().Verify(leaf, []*x509.Certificate{ca}, time.Now())
+	assert.ErrorIs(t, err, ErrUntrustedChain)
+}
+
+func TestTrustStore_AddPEM(t *testing.T) {
+	err := NewTrustStore().AddPEM([]byte("sin certificados"))
+	assert.ErrorIs(t, err, ErrNoCertificate)
+}
@@ -0,0 +1,384 @@
+// Package ciiu contiene la Clasificación Industrial Internacional Uniforme (CIIU 4.0) en
+// su versión para Ecuador, y permite asociar la actividad económica de un contribuyente
+// con su código y sección.
+package ciiu
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// catalogCSV contiene las secciones y divisiones de la CIIU 4.0.
+//
+//go:embed ciiu.csv
+var catalogCSV string
+
+// Level es el nivel de un código dentro de la clasificación.
+type Level int
+
+const (
+	// LevelSection es una sección (una letra, por ejemplo "G").
+	LevelSection Level = iota
+
+	// LevelDivision es una división (2 dígitos, por ejemplo "G47").
+	LevelDivision
+
+	// LevelGroup es un grupo (3 dígitos, por ejemplo "G471").
+	LevelGroup
+
+	// LevelClass es una clase (4 dígitos, por ejemplo "G4711").
+	LevelClass
+
+	// LevelSubclass es una subclase (5 dígitos, por ejemplo "G4711.0").
+	LevelSubclass
+
+	// LevelActivity es una actividad económica (6 dígitos, por ejemplo "G4711.01").
+	LevelActivity
+)
+
+// String devuelve el nombre del nivel.
+func (l Level) String() string {
+	switch l {
+	case LevelSection:
+		return "SECCIÓN"
+	case LevelDivision:
+		return "DIVISIÓN"
+	case LevelGroup:
+		return "GRUPO"
+	case LevelClass:
+		return "CLASE"
+	case LevelSubclass:
+		return "SUBCLASE"
+	default:
+		return "ACTIVIDAD"
+	}
+}
+
+// Activity es una entrada de la clasificación.
+type Activity struct {
+	// Code: Código con la letra de la sección, por ejemplo "G4711.01".
+	Code string
+
+	// Section: Letra de la sección.
+	Section string
+
+	// Description: Descripción de la actividad.
+	Description string
+
+	// Level: Nivel del código.
+	Level Level
+}
+
+// sectionRange es el rango de divisiones de una sección.
+type sectionRange struct {
+	letter   string
+	from, to int
+}
+
+// sections asigna a cada división (2 dígitos) la letra de su sección.
+var sections = []sectionRange{
+	{"A", 1, 3}, {"B", 5, 9}, {"C", 10, 33}, {"D", 35, 35}, {"E", 36, 39},
+	{"F", 41, 43}, {"G", 45, 47}, {"H", 49, 53}, {"I", 55, 56}, {"J", 58, 63},
+	{"K", 64, 66}, {"L", 68, 68}, {"M", 69, 75}, {"N", 77, 82}, {"O", 84, 84},
+	{"P", 85, 85}, {"Q", 86, 88}, {"R", 90, 93}, {"S", 94, 96}, {"T", 97, 98},
+	{"U", 99, 99},
+}
+
+// Catalog es un catálogo CIIU indexado por código y por palabras de su descripción.
+type Catalog struct {
+	activities map[string]*Activity
+
+	// tokens contiene las palabras normalizadas de cada descripción.
+	tokens map[*Activity][]string
+
+	// weights es el peso de cada palabra según su frecuencia en el catálogo.
+	weights map[string]float64
+}
+
+// embeddedCatalog es el catálogo incluido en el paquete, cargado en el primer uso.
+var embeddedCatalog = sync.OnceValue(func() *Catalog {
+	catalog := &Catalog{activities: map[string]*Activity{}}
+	if err := catalog.load(strings.NewReader(catalogCSV)); err != nil {
+		panic(err)
+	}
+	catalog.index()
+
+	return catalog
+})
+
+// defaultCatalog es el catálogo asignado con SetDefault.
+var defaultCatalog atomic.Pointer[Catalog]
+
+// Default devuelve el catálogo usado por MatchActivity: el asignado con SetDefault o, si
+// no se asignó ninguno, el incluido en el paquete. El catálogo incluido contiene solo las
+// secciones y divisiones de la CIIU 4.0 (por ejemplo "G47"); no contiene los grupos, las
+// clases ni las actividades económicas de 6 dígitos, por lo que con él MatchActivity
+// devuelve a lo sumo una división.
+func Default() *Catalog {
+	if catalog := defaultCatalog.Load(); catalog != nil {
+		return catalog
+	}
+
+	return embeddedCatalog()
+}
+
+// SetDefault reemplaza el catálogo usado por Default y MatchActivity. Para asociar las
+// descripciones con las actividades económicas de 6 dígitos (por ejemplo "G4772.01") debe
+// asignarse el archivo completo de la CIIU 4.0 del INEC, cargado con LoadCatalog. Un
+// catálogo nil restablece el catálogo incluido en el paquete.
+func SetDefault(catalog *Catalog) {
+	defaultCatalog.Store(catalog)
+}
+
+// LoadCatalog lee un catálogo CIIU en formato CSV separado por punto y coma, con las
+// columnas CODIGO y DESCRIPCION. Los códigos pueden incluir la letra de la sección y el
+// punto ("G4711.01") o solo los dígitos ("471101"). Las secciones y divisiones del
+// catálogo incluido se conservan si el archivo no las contiene.
+//
+// Los archivos exportados de la publicación del INEC, separados por comas o con otras
+// columnas (como el nivel), se aceptan si su cabecera incluye las columnas CÓDIGO y
+// DESCRIPCIÓN.
+func LoadCatalog(r io.Reader) (*Catalog, error) {
+	catalog := &Catalog{activities: maps.Clone(embeddedCatalog().activities)}
+	if err := catalog.load(r); err != nil {
+		return nil, err
+	}
+
+	catalog.index()
+	return catalog, nil
+}
+
+// load agrega las filas de un archivo CSV al catálogo.
+func (c *Catalog) load(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return errors.Join(ErrInvalidCatalog, err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.Comma = ';'
+	if line, _, _ := bytes.Cut(data, []byte("\n")); !bytes.Contains(line, []byte(";")) && bytes.Contains(line, []byte(",")) {
+		reader.Comma = ','
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.LazyQuotes = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return errors.Join(ErrInvalidCatalog, err)
+	}
+
+	// Sin cabecera reconocida, el código es la primera columna y la descripción el resto.
+	code, description, last := 0, 1, true
+	if len(rows) > 0 {
+		if i, j := column(rows[0], "CODIGO"), column(rows[0], "DESCRIPCION"); i >= 0 && j >= 0 {
+			code, description, last = i, j, j == len(rows[0])-1
+			rows[0] = nil
+		}
+	}
+
+	added := 0
+	for i, row := range rows {
+		if len(row) < 2 || len(row) <= max(code, description) {
+			continue
+		}
+
+		activity, err := parseCode(row[code])
+		if err != nil {
+			// La primera fila puede ser la cabecera.
+			if i == 0 {
+				continue
+			}
+
+			return fmt.Errorf("%w: fila %d: %w", ErrInvalidCatalog, i+1, err)
+		}
+
+		if last {
+			// Las descripciones pueden contener punto y coma.
+			activity.Description = strings.TrimSpace(strings.Join(row[description:], "; "))
+		} else {
+			activity.Description = strings.TrimSpace(row[description])
+		}
+		c.activities[key(activity)] = activity
+		added++
+	}
+
+	if added == 0 {
+		return ErrInvalidCatalog
+	}
+
+	return nil
+}
+
+// column devuelve la posición de una columna de la cabecera, sin distinguir mayúsculas
+// ni tildes, o -1 si no existe.
+func column(header []string, name string) int {
+	return slices.IndexFunc(header, func(value string) bool {
+		return strings.ToUpper(accents.Replace(strings.TrimSpace(value))) == name
+	})
+}
+
+// index calcula las palabras de cada descripción y su peso.
+func (c *Catalog) index() {
+	c.tokens = make(map[*Activity][]string, len(c.activities))
+	frequency := map[string]int{}
+
+	for _, activity := range c.activities {
+		tokens := tokenize(activity.Description)
+		c.tokens[activity] = tokens
+
+		for _, token := range tokens {
+			frequency[token]++
+		}
+	}
+
+	c.weights = make(map[string]float64, len(frequency))
+	for token, n := range frequency {
+		c.weights[token] = idf(len(c.activities), n)
+	}
+}
+
+// Len devuelve el número de entradas del catálogo.
+func (c *Catalog) Len() int {
+	return len(c.activities)
+}
+
+// Lookup busca una entrada por su código, con o sin la letra de la sección y el punto
+// ("G4711.01", "4711.01" o "471101").
+func (c *Catalog) Lookup(code string) (*Activity, bool) {
+	activity, err := parseCode(code)
+	if err != nil {
+		return nil, false
+	}
+
+	found, ok := c.activities[key(activity)]
+	return found, ok
+}
+
+// SectionOf devuelve la sección a la que pertenece un código.
+func (c *Catalog) SectionOf(code string) (*Activity, bool) {
+	activity, err := parseCode(code)
+	if err != nil {
+		return nil, false
+	}
+
+	section, ok := c.activities[activity.Section]
+	return section, ok
+}
+
+// Search busca entradas por código o por descripción y las devuelve ordenadas por código.
+//
+// Si la consulta es un código, se devuelven las entradas que comienzan con él (por
+// ejemplo "G47" devuelve la división y todas sus clases). En otro caso, se devuelven las
+// entradas cuya descripción contiene todas las palabras de la consulta.
+func (c *Catalog) Search(query string) []*Activity {
+	var result []*Activity
+
+	if prefix, err := parseCode(query); err == nil {
+		for _, activity := range c.activities {
+			if prefix.Level == LevelSection && activity.Section == prefix.Section ||
+				activity.Level > LevelSection && strings.HasPrefix(key(activity), key(prefix)) {
+				result = append(result, activity)
+			}
+		}
+	} else if words := tokenize(query); len(words) > 0 {
+		for activity, tokens := range c.tokens {
+			if containsAll(tokens, words) {
+				result = append(result, activity)
+			}
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *Activity) int {
+		return strings.Compare(sortKey(a), sortKey(b))
+	})
+
+	return result
+}
+
+// parseCode interpreta un código CIIU y devuelve una entrada sin descripción.
+func parseCode(code string) (*Activity, error) {
+	value := strings.ToUpper(strings.NewReplacer(".", "", " ", "").Replace(code))
+	if value == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCode, code)
+	}
+
+	letter := ""
+	if value[0] >= 'A' && value[0] <= 'Z' {
+		letter, value = value[:1], value[1:]
+	}
+
+	if value == "" {
+		if !slices.ContainsFunc(sections, func(s sectionRange) bool { return s.letter == letter }) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidCode, code)
+		}
+
+		return &Activity{Code: letter, Section: letter, Level: LevelSection}, nil
+	}
+
+	division, err := strconv.Atoi(value[:min(2, len(value))])
+	if err != nil || len(value) < 2 || strings.ContainsFunc(value, func(r rune) bool { return r < '0' || r > '9' }) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCode, code)
+	}
+
+	section := ""
+	for _, s := range sections {
+		if division >= s.from && division <= s.to {
+			section = s.letter
+			break
+		}
+	}
+
+	if section == "" || (letter != "" && letter != section) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidCode, code)
+	}
+
+	activity := &Activity{Section: section, Level: Level(min(len(value)-1, int(LevelActivity)))}
+	activity.Code = section + value
+	if len(value) > 4 {
+		activity.Code = section + value[:4] + "." + value[4:]
+	}
+
+	return activity, nil
+}
+
+// key devuelve la clave de una entrada en el catálogo: la letra para las secciones y
+// los dígitos para los demás niveles.
+func key(activity *Activity) string {
+	if activity.Level == LevelSection {
+		return activity.Section
+	}
+
+	return strings.ReplaceAll(activity.Code[1:], ".", "")
+}
+
+// sortKey ordena las secciones antes que sus divisiones.
+func sortKey(activity *Activity) string {
+	if activity.Level == LevelSection {
+		return activity.Section
+	}
+
+	return activity.Section + key(activity)
+}
+
+// containsAll indica si tokens contiene todas las palabras indicadas.
+func containsAll(tokens, words []string) bool {
+	for _, word := range words {
+		if !slices.Contains(tokens, word) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,87 @@
+package ciiu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefault(t *testing.T) {
+	catalog := Default()
+	assert.Equal(t, 21+88, catalog.Len())
+
+	for _, section := range sections {
+		activity, ok := catalog.Lookup(section.letter)
+		require.True(t, ok, section.letter)
+		assert.Equal(t, LevelSection, activity.Level)
+	}
+}
+
+func TestCatalog_Lookup(t *testing.T) {
+	catalog := Default()
+
+	for _, code := range []string{"G47", "47", "g47"} {
+		activity, ok := catalog.Lookup(code)
+		require.True(t, ok, code)
+		assert.Equal(t, "G47", activity.Code)
+		assert.Equal(t, "G", activity.Section)
+		assert.Equal(t, LevelDivision, activity.Level)
+	}
+
+	section, ok := catalog.SectionOf("G4711.01")
+	require.True(t, ok)
+	assert.Equal(t, "COMERCIO AL POR MAYOR Y AL POR MENOR; REPARACIÓN DE VEHÍCULOS AUTOMOTORES Y MOTOCICLETAS.", section.Description)
+
+	_, ok = catalog.Lookup("4711.01")
+	assert.False(t, ok)
+
+	for _, code := range []string{"", "04", "A47", "Z", "4X"} {
+		_, ok = catalog.Lookup(code)
+		assert.False(t, ok, code)
+	}
+}
+
+func TestCatalog_Search(t *testing.T) {
+	catalog := Default()
+
+	codes := func(activities []*Activity) []string {
+		var result []string
+		for _, activity := range activities {
+			result = append(result, activity.Code)
+		}
+		return result
+	}
+
+	assert.Equal(t, []string{"G", "G45", "G46", "G47"}, codes(catalog.Search("G")))
+	assert.Equal(t, []string{"G47"}, codes(catalog.Search("47")))
+	assert.Equal(t, []string{"G", "G45", "G46"}, codes(catalog.Search("por mayor")))
+	assert.Equal(t, []string{"J62", "M70"}, codes(catalog.Search("consultoría")))
+	assert.Empty(t, catalog.Search("de"))
+}
+
+func TestLoadCatalog(t *testing.T) {
+	catalog, err := LoadCatalog(strings.NewReader(
+		"CODIGO;DESCRIPCION\n" +
+			"G4772.01;VENTA AL POR MENOR DE PRODUCTOS FARMACÉUTICOS EN ESTABLECIMIENTOS ESPECIALIZADOS.\n" +
+			"477201;DUPLICADO SIN SECCIÓN.\n" +
+			"G4772;VENTA AL POR MENOR DE PRODUCTOS FARMACÉUTICOS Y MEDICINALES, COSMÉTICOS Y ARTÍCULOS DE TOCADOR EN COMERCIOS ESPECIALIZADOS.\n"))
+	require.NoError(t, err)
+	assert.Equal(t, Default().Len()+2, catalog.Len())
+
+	activity, ok := catalog.Lookup("4772.01")
+	require.True(t, ok)
+	assert.Equal(t, "G4772.01", activity.Code)
+	assert.Equal(t, LevelActivity, activity.Level)
+	assert.Equal(t, "DUPLICADO SIN SECCIÓN.", activity.Description)
+
+	_, ok = catalog.Lookup("G47")
+	assert.True(t, ok)
+
+	_, err = LoadCatalog(strings.NewReader("CODIGO;DESCRIPCION\nA47;X\n"))
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+
+	_, err = LoadCatalog(strings.NewReader(""))
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+}
@@ -0,0 +1,8 @@
+package ciiu
+
+import "errors"
+
+var (
+	ErrInvalidCatalog = errors.New("El catálogo no tiene el formato CODIGO;DESCRIPCION de la CIIU 4.0")
+	ErrInvalidCode    = errors.New("El código CIIU no es válido")
+)
@@ -0,0 +1,180 @@
+package ciiu
+
+import (
+	"math"
+	"slices"
+	"strings"
+)
+
+// Match es el resultado de asociar una descripción con el catálogo.
+type Match struct {
+	// Activity: Entrada más parecida a la descripción.
+	Activity *Activity
+
+	// Section: Sección de la entrada.
+	Section *Activity
+
+	// Score: Similitud entre 0 y 1.
+	Score float64
+}
+
+// MatchActivity asocia una descripción con el catálogo devuelto por Default. Con el
+// catálogo incluido en el paquete, el resultado es una sección o una división.
+func MatchActivity(description string) (*Match, bool) {
+	return Default().Match(description)
+}
+
+// Match asocia una descripción de actividad económica, como Contributor.EconomicActivity,
+// con la división, clase o actividad del catálogo más parecida.
+//
+// La similitud compara las palabras de ambas descripciones, sin tildes, plurales ni
+// palabras vacías, y pondera más las palabras poco frecuentes en el catálogo. Ante
+// empates se prefiere la entrada más específica. Devuelve false si ninguna entrada
+// comparte palabras con la descripción.
+func (c *Catalog) Match(description string) (*Match, bool) {
+	words := tokenize(description)
+	if len(words) == 0 {
+		return nil, false
+	}
+
+	var best *Match
+	for activity, tokens := range c.tokens {
+		// Las secciones resumen sus divisiones; se informan en Match.Section.
+		if activity.Level == LevelSection {
+			continue
+		}
+
+		score := c.similarity(words, tokens)
+		if score == 0 {
+			continue
+		}
+
+		if best == nil || score > best.Score ||
+			score == best.Score && (activity.Level > best.Activity.Level ||
+				activity.Level == best.Activity.Level && activity.Code < best.Activity.Code) {
+			best = &Match{Activity: activity, Score: score}
+		}
+	}
+
+	if best == nil {
+		return nil, false
+	}
+
+	best.Section = c.activities[best.Activity.Section]
+
+	return best, true
+}
+
+// similarity calcula el coeficiente de Dice ponderado entre dos conjuntos de palabras.
+func (c *Catalog) similarity(words, tokens []string) float64 {
+	var common, total float64
+
+	for _, word := range words {
+		weight := c.weight(word)
+		total += weight
+
+		if slices.Contains(tokens, word) {
+			common += weight
+		}
+	}
+
+	if common == 0 {
+		return 0
+	}
+
+	for _, token := range tokens {
+		total += c.weight(token)
+	}
+
+	return 2 * common / total
+}
+
+// weight devuelve el peso de una palabra; las que no aparecen en el catálogo tienen el
+// peso máximo.
+func (c *Catalog) weight(word string) float64 {
+	if weight, ok := c.weights[word]; ok {
+		return weight
+	}
+
+	return idf(len(c.activities), 1)
+}
+
+// idf calcula la frecuencia inversa de una palabra que aparece en n de total entradas.
+func idf(total, n int) float64 {
+	return math.Log(1 + float64(total)/float64(n))
+}
+
+// stopwords son las palabras que no se consideran al comparar descripciones.
+var stopwords = map[string]bool{
+	"A": true, "AL": true, "CON": true, "DE": true, "DEL": true, "E": true, "EL": true,
+	"EN": true, "EXCEPTO": true, "LA": true, "LAS": true, "LO": true, "LOS": true,
+	"NCP": true, "NO": true, "O": true, "PARA": true, "POR": true, "SIN": true,
+	"SU": true, "SUS": true, "U": true, "Y": true,
+	"ACTIVIDAD": true, "OTRA": true, "OTRO": true, "TIPO": true,
+}
+
+// synonyms unifica las palabras que el SRI y la CIIU usan para lo mismo.
+var synonyms = map[string]string{
+	"VENTA":       "COMERCIO",
+	"VENTAS":      "COMERCIO",
+	"COMPRA":      "COMERCIO",
+	"RESTAURANTE": "ALIMENTO",
+	"COMIDA":      "ALIMENTO",
+	"COMIDAS":     "ALIMENTO",
+	"SOFTWARE":    "INFORMATICA",
+	"ENERGIA":     "ELECTRICIDAD",
+	"ELECTRICA":   "ELECTRICIDAD",
+	"ELECTRICO":   "ELECTRICIDAD",
+	"CARRETERA":   "TERRESTRE",
+}
+
+// accents reemplaza las vocales con tilde y la eñe por su letra base.
+var accents = strings.NewReplacer(
+	"Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U", "Ü", "U", "Ñ", "N",
+	"á", "A", "é", "E", "í", "I", "ó", "O", "ú", "U", "ü", "U", "ñ", "N",
+)
+
+// tokenize devuelve las palabras normalizadas de una descripción, sin repetir.
+func tokenize(description string) []string {
+	words := strings.FieldsFunc(strings.ToUpper(accents.Replace(description)), func(r rune) bool {
+		return (r < 'A' || r > 'Z') && (r < '0' || r > '9') && r != '.'
+	})
+
+	var tokens []string
+	for _, word := range words {
+		word = strings.ReplaceAll(word, ".", "")
+		if synonym, ok := synonyms[word]; ok {
+			word = synonym
+		}
+
+		word = stem(word)
+		if word == "" || stopwords[word] || slices.Contains(tokens, word) {
+			continue
+		}
+
+		tokens = append(tokens, word)
+	}
+
+	return tokens
+}
+
+// stem elimina el plural y el género de una palabra ("PRODUCTOS" y "PRODUCTO" resultan
+// en "PRODUCT"; "ACTIVIDADES" en "ACTIVIDAD").
+func stem(word string) string {
+	if stopwords[word] || len(word) <= 4 {
+		return word
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ES") && strings.ContainsRune("DLNRZ", rune(word[len(word)-3])):
+		word = word[:len(word)-2]
+	case strings.HasSuffix(word, "S"):
+		word = word[:len(word)-1]
+	}
+
+	if len(word) > 4 && (strings.HasSuffix(word, "O") || strings.HasSuffix(word, "A")) {
+		word = word[:len(word)-1]
+	}
+
+	return word
+}
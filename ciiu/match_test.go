@@ -0,0 +1,93 @@
+package ciiu
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchActivity(t *testing.T) {
+	tests := []struct {
+		description string
+		code        string
+		section     string
+	}{
+		{"VENTA AL POR MENOR DE PRODUCTOS FARMACEUTICOS EN ESTABLECIMIENTOS ESPECIALIZADOS.", "G47", "G"},
+		{"VENTA AL POR MAYOR DE MATERIALES DE CONSTRUCCION.", "G46", "G"},
+		{"ACTIVIDADES DE RESTAURANTES Y DE SERVICIO MOVIL DE COMIDAS.", "I56", "I"},
+		{"Actividades de consultoría informática.", "J62", "J"},
+		{"TRANSPORTE DE CARGA POR CARRETERA.", "H49", "H"},
+		{"CONSTRUCCION DE EDIFICIOS RESIDENCIALES.", "F41", "F"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			match, ok := MatchActivity(tt.description)
+			require.True(t, ok)
+			assert.Equal(t, tt.code, match.Activity.Code)
+			assert.Equal(t, tt.section, match.Section.Code)
+			assert.Greater(t, match.Score, 0.0)
+			assert.LessOrEqual(t, match.Score, 1.0)
+		})
+	}
+
+	_, ok := MatchActivity("DE LA Y")
+	assert.False(t, ok)
+
+	_, ok = MatchActivity("XYZ")
+	assert.False(t, ok)
+}
+
+func TestCatalog_MatchSpecific(t *testing.T) {
+	catalog, err := LoadCatalog(strings.NewReader(
+		"G4772.01;VENTA AL POR MENOR DE PRODUCTOS FARMACÉUTICOS EN ESTABLECIMIENTOS ESPECIALIZADOS.\n" +
+			"C2100.01;FABRICACIÓN DE SUSTANCIAS MEDICINALES ACTIVAS.\n"))
+	require.NoError(t, err)
+
+	match, ok := catalog.Match("VENTA AL POR MENOR DE PRODUCTOS FARMACEUTICOS EN ESTABLECIMIENTOS ESPECIALIZADOS")
+	require.True(t, ok)
+	assert.Equal(t, "G4772.01", match.Activity.Code)
+	assert.InDelta(t, 1.0, match.Score, 1e-9)
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, []string{"COMERCI", "MENOR", "PRODUCT"}, tokenize("Ventas al por menor de productos, producto"))
+	assert.Equal(t, []string{"MAQUINARI", "EQUIP"}, tokenize("MAQUINARIA Y EQUIPO N.C.P."))
+	assert.Equal(t, "ACTIVIDAD", stem("ACTIVIDADES"))
+	assert.Equal(t, "MOTOCICLET", stem("MOTOCICLETAS"))
+}
+
+func TestSetDefault(t *testing.T) {
+	catalog, err := LoadCatalog(strings.NewReader(
+		"\ufeffNIVEL,CÓDIGO,DESCRIPCIÓN\n" +
+			"4,G4772,\"VENTA AL POR MENOR DE PRODUCTOS FARMACÉUTICOS Y MEDICINALES, COSMÉTICOS Y ARTÍCULOS DE TOCADOR EN COMERCIOS ESPECIALIZADOS.\"\n" +
+			"6,G4772.01,VENTA AL POR MENOR DE PRODUCTOS FARMACÉUTICOS EN ESTABLECIMIENTOS ESPECIALIZADOS (FARMACIAS).\n" +
+			"6,G4772.02,VENTA AL POR MENOR DE ARTÍCULOS DE PERFUMERÍA Y COSMÉTICOS EN ESTABLECIMIENTOS ESPECIALIZADOS.\n"))
+	require.NoError(t, err)
+
+	activity, ok := catalog.Lookup("G4772")
+	require.True(t, ok)
+	assert.Equal(t, LevelClass, activity.Level)
+	assert.True(t, strings.HasPrefix(activity.Description, "VENTA AL POR MENOR DE PRODUCTOS FARMACÉUTICOS Y MEDICINALES, COSMÉTICOS"))
+
+	SetDefault(catalog)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	// Con el catálogo completo, MatchActivity asocia la actividad económica.
+	match, ok := MatchActivity("VENTA AL POR MENOR DE PRODUCTOS FARMACEUTICOS EN FARMACIAS.")
+	require.True(t, ok)
+	assert.Equal(t, "G4772.01", match.Activity.Code)
+	assert.Equal(t, LevelActivity, match.Activity.Level)
+	assert.Equal(t, "G", match.Section.Code)
+
+	match, ok = MatchActivity("VENTA AL POR MENOR DE COSMETICOS Y PERFUMERIA.")
+	require.True(t, ok)
+	assert.Equal(t, "G4772.02", match.Activity.Code)
+
+	SetDefault(nil)
+	match, ok = MatchActivity("VENTA AL POR MENOR DE PRODUCTOS FARMACEUTICOS EN FARMACIAS.")
+	require.True(t, ok)
+	assert.Equal(t, "G47", match.Activity.Code)
+}
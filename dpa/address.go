@@ -0,0 +1,84 @@
+package dpa
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Address es la dirección de un establecimiento separada en sus partes.
+type Address struct {
+	// Province: Provincia, según el catálogo.
+	Province *Province
+
+	// Canton: Cantón, según el catálogo.
+	Canton *Canton
+
+	// Parish: Parroquia, según el catálogo.
+	Parish *Parish
+
+	// ParishName: Parroquia tal como aparece en la dirección.
+	ParishName string
+
+	// Street: Calles, número y referencias.
+	Street string
+}
+
+// ParseAddress separa una dirección del catastro RUC con el catálogo devuelto por Default.
+func ParseAddress(value string) (*Address, error) {
+	return Default().ParseAddress(value)
+}
+
+// ParseAddress separa una dirección del catastro RUC con el formato
+// "PROVINCIA / CANTÓN / PARROQUIA / CALLES", como el campo Establishment.Address, y valida
+// la provincia, el cantón y la parroquia.
+//
+// Si una parte no existe en el catálogo se devuelve el error correspondiente junto con
+// las partes reconocidas hasta ese punto, de modo que la dirección pueda segmentarse al
+// menos por provincia. Si el catálogo no incluye las parroquias del cantón, la parroquia
+// no puede validarse y se devuelve ErrNoParishes.
+func (c *Catalog) ParseAddress(value string) (*Address, error) {
+	parts := strings.SplitN(value, "/", 4)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidAddress, value)
+	}
+
+	address := &Address{}
+	if len(parts) > 2 {
+		address.ParishName = parts[2]
+	}
+	if len(parts) > 3 {
+		address.Street = parts[3]
+	}
+
+	province, ok := c.Province(parts[0])
+	if !ok {
+		return address, fmt.Errorf("%w: %s", ErrUnknownProvince, parts[0])
+	}
+	address.Province = province
+
+	canton, ok := c.Canton(province, parts[1])
+	if !ok {
+		return address, fmt.Errorf("%w: %s / %s", ErrUnknownCanton, parts[0], parts[1])
+	}
+	address.Canton = canton
+
+	if address.ParishName == "" {
+		return address, fmt.Errorf("%w: %q", ErrInvalidAddress, value)
+	}
+
+	if len(canton.Parishes) == 0 {
+		return address, fmt.Errorf("%w: %s / %s", ErrNoParishes, canton.Name, address.ParishName)
+	}
+
+	parish, ok := c.Parish(canton, address.ParishName)
+	if !ok {
+		return address, fmt.Errorf("%w: %s / %s", ErrUnknownParish, canton.Name, address.ParishName)
+	}
+	address.Parish = parish
+
+	return address, nil
+}
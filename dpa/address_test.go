@@ -0,0 +1,83 @@
+package dpa
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAddress(t *testing.T) {
+	// The embedded catalog has no parishes, so they cannot be validated.
+	address, err := ParseAddress("PICHINCHA / QUITO / IÑAQUITO / AV. AMAZONAS N36-152 Y NACIONES UNIDAS / EDIFICIO XYZ")
+	assert.ErrorIs(t, err, ErrNoParishes)
+	assert.Equal(t, "17", address.Province.Code)
+	assert.Equal(t, "1701", address.Canton.Code)
+	assert.Nil(t, address.Parish)
+	assert.Equal(t, "IÑAQUITO", address.ParishName)
+	assert.Equal(t, "AV. AMAZONAS N36-152 Y NACIONES UNIDAS / EDIFICIO XYZ", address.Street)
+
+	address, err = ParseAddress("Tungurahua/Baños/Baños de Agua Santa")
+	assert.ErrorIs(t, err, ErrNoParishes)
+	assert.Equal(t, "1802", address.Canton.Code)
+	assert.Empty(t, address.Street)
+
+	address, err = ParseAddress("CHIMBORAZO / QUITO / CENTRO")
+	assert.ErrorIs(t, err, ErrUnknownCanton)
+	assert.Equal(t, "06", address.Province.Code)
+	assert.Nil(t, address.Canton)
+
+	_, err = ParseAddress("EXTERIOR / MIAMI")
+	assert.ErrorIs(t, err, ErrUnknownProvince)
+
+	_, err = ParseAddress("AV. AMAZONAS Y NACIONES UNIDAS")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+
+	address, err = ParseAddress("PICHINCHA / QUITO")
+	assert.ErrorIs(t, err, ErrInvalidAddress)
+	assert.Equal(t, "1701", address.Canton.Code)
+}
+
+func TestCatalog_ParseAddressParish(t *testing.T) {
+	catalog, err := LoadCatalog(strings.NewReader("17;PICHINCHA\n1701;QUITO\n170150;IÑAQUITO\n"))
+	require.NoError(t, err)
+
+	address, err := catalog.ParseAddress("PICHINCHA / QUITO / INAQUITO / AV. AMAZONAS")
+	require.NoError(t, err)
+	assert.Equal(t, "170150", address.Parish.Code)
+
+	address, err = catalog.ParseAddress("PICHINCHA / QUITO / CUMBAYA / AV. INTEROCEANICA")
+	assert.ErrorIs(t, err, ErrUnknownParish)
+	assert.Equal(t, "1701", address.Canton.Code)
+	assert.Nil(t, address.Parish)
+
+	// A canton without parishes in the catalog is reported, not skipped.
+	catalog, err = LoadCatalog(strings.NewReader("17;PICHINCHA\n1701;QUITO\n1702;CAYAMBE\n170150;IÑAQUITO\n"))
+	require.NoError(t, err)
+
+	address, err = catalog.ParseAddress("PICHINCHA / CAYAMBE / AYORA")
+	assert.ErrorIs(t, err, ErrNoParishes)
+	assert.Equal(t, "1702", address.Canton.Code)
+	assert.Nil(t, address.Parish)
+}
+
+func TestSetDefault(t *testing.T) {
+	catalog, err := LoadCatalog(strings.NewReader("17;PICHINCHA\n1701;QUITO\n170150;IÑAQUITO\n"))
+	require.NoError(t, err)
+
+	SetDefault(catalog)
+	t.Cleanup(func() { SetDefault(nil) })
+
+	// Con el catálogo completo, ParseAddress valida también las parroquias.
+	_, err = ParseAddress("PICHINCHA / QUITO / CUMBAYA / AV. INTEROCEANICA")
+	assert.ErrorIs(t, err, ErrUnknownParish)
+
+	address, err := ParseAddress("PICHINCHA / QUITO / IÑAQUITO / AV. AMAZONAS")
+	require.NoError(t, err)
+	assert.Equal(t, "170150", address.Parish.Code)
+
+	SetDefault(nil)
+	_, err = ParseAddress("PICHINCHA / QUITO / IÑAQUITO / AV. AMAZONAS")
+	assert.ErrorIs(t, err, ErrNoParishes)
+}
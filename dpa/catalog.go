@@ -0,0 +1,353 @@
+// Package dpa contiene el catálogo de la División Político Administrativa (DPA) del INEC
+// y permite estructurar las direcciones de los establecimientos del catastro RUC.
+package dpa
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pinzlab/sricore/id"
+)
+
+// catalogCSV contiene las provincias y cantones de la DPA del INEC. Las provincias usan
+// los mismos códigos (01 a 24) que los dos primeros dígitos de la cédula y el RUC.
+//
+//go:embed dpa.csv
+var catalogCSV string
+
+// Province es una provincia del Ecuador.
+type Province struct {
+	// Code: Código INEC de 2 dígitos.
+	Code string
+
+	// Name: Nombre de la provincia.
+	Name string
+
+	// Cantons: Cantones de la provincia.
+	Cantons []*Canton
+}
+
+// Canton es un cantón de una provincia.
+type Canton struct {
+	// Code: Código INEC de 4 dígitos (provincia y cantón).
+	Code string
+
+	// Name: Nombre del cantón.
+	Name string
+
+	// Province: Provincia a la que pertenece el cantón.
+	Province *Province
+
+	// Parishes: Parroquias del cantón, si el catálogo las incluye.
+	Parishes []*Parish
+}
+
+// Parish es una parroquia urbana o rural de un cantón.
+type Parish struct {
+	// Code: Código INEC de 6 dígitos (provincia, cantón y parroquia).
+	Code string
+
+	// Name: Nombre de la parroquia.
+	Name string
+
+	// Canton: Cantón al que pertenece la parroquia.
+	Canton *Canton
+}
+
+// Catalog es un catálogo de la DPA indexado por código y por nombre.
+type Catalog struct {
+	provinces []*Province
+
+	provincesByCode map[string]*Province
+	cantonsByCode   map[string]*Canton
+	parishesByCode  map[string]*Parish
+
+	// names indexa los nombres normalizados por el código del nivel superior
+	// ("" para las provincias).
+	names map[string]any
+}
+
+// embeddedCatalog es el catálogo incluido en el paquete, cargado en el primer uso.
+var embeddedCatalog = sync.OnceValue(func() *Catalog {
+	catalog, err := LoadCatalog(strings.NewReader(catalogCSV))
+	if err != nil {
+		panic(err)
+	}
+
+	return catalog
+})
+
+// defaultCatalog es el catálogo asignado con SetDefault.
+var defaultCatalog atomic.Pointer[Catalog]
+
+// Default devuelve el catálogo usado por ParseAddress: el asignado con SetDefault o, si
+// no se asignó ninguno, el incluido en el paquete, con las 24 provincias y sus cantones.
+// El catálogo incluido no contiene las parroquias, por lo que con él ParseAddress
+// devuelve ErrNoParishes para toda dirección.
+func Default() *Catalog {
+	if catalog := defaultCatalog.Load(); catalog != nil {
+		return catalog
+	}
+
+	return embeddedCatalog()
+}
+
+// SetDefault reemplaza el catálogo usado por Default y ParseAddress, normalmente por el
+// archivo completo de la DPA del INEC cargado con LoadCatalog. Un catálogo nil
+// restablece el catálogo incluido en el paquete.
+func SetDefault(catalog *Catalog) {
+	defaultCatalog.Store(catalog)
+}
+
+// LoadCatalog lee un catálogo de la DPA en formato CSV separado por punto y coma, con
+// las columnas CODIGO, NOMBRE y, opcionalmente, ALIAS (nombres alternativos separados
+// por |). El nivel de cada fila se determina por la longitud del código: 2 dígitos para
+// provincias, 4 para cantones y 6 para parroquias.
+//
+// También acepta la codificación de la DPA publicada por el INEC, con una parroquia por
+// fila y las columnas DPA_PROVIN, DPA_DESPRO, DPA_CANTON, DPA_DESCAN, DPA_PARROQ y
+// DPA_DESPAR, separadas por punto y coma o por comas.
+func LoadCatalog(r io.Reader) (*Catalog, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCatalog, err)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	reader.Comma = ';'
+	if line, _, _ := bytes.Cut(data, []byte("\n")); !bytes.Contains(line, []byte(";")) && bytes.Contains(line, []byte(",")) {
+		reader.Comma = ','
+	}
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Join(ErrInvalidCatalog, err)
+	}
+
+	if len(rows) > 0 {
+		if columns := inecColumns(rows[0]); columns != nil {
+			rows = expandINEC(columns, rows[1:])
+		}
+	}
+
+	rows = slices.DeleteFunc(rows, func(row []string) bool {
+		return len(row) < 2 || !isCode(strings.TrimSpace(row[0]))
+	})
+
+	// Los niveles superiores se cargan primero.
+	slices.SortStableFunc(rows, func(a, b []string) int {
+		return len(strings.TrimSpace(a[0])) - len(strings.TrimSpace(b[0]))
+	})
+
+	c := &Catalog{
+		provincesByCode: map[string]*Province{},
+		cantonsByCode:   map[string]*Canton{},
+		parishesByCode:  map[string]*Parish{},
+		names:           map[string]any{},
+	}
+
+	for _, row := range rows {
+		if err := c.add(row); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(c.provinces) == 0 {
+		return nil, ErrInvalidCatalog
+	}
+
+	return c, nil
+}
+
+// inecHeader son las columnas de la codificación de la DPA del INEC, en el orden de los
+// niveles: código y nombre de la provincia, del cantón y de la parroquia.
+var inecHeader = []string{"DPA_PROVIN", "DPA_DESPRO", "DPA_CANTON", "DPA_DESCAN", "DPA_PARROQ", "DPA_DESPAR"}
+
+// inecColumns devuelve la posición de cada columna de inecHeader en la cabecera, o nil si
+// la cabecera no corresponde a la codificación del INEC.
+func inecColumns(header []string) []int {
+	columns := make([]int, len(inecHeader))
+	for i, name := range inecHeader {
+		columns[i] = slices.IndexFunc(header, func(column string) bool {
+			return strings.EqualFold(strings.TrimSpace(column), name)
+		})
+		if columns[i] < 0 {
+			return nil
+		}
+	}
+
+	return columns
+}
+
+// expandINEC convierte las filas de la codificación del INEC, una por parroquia, en filas
+// CODIGO;NOMBRE de cada provincia, cantón y parroquia.
+func expandINEC(columns []int, rows [][]string) [][]string {
+	var expanded [][]string
+	seen := map[string]bool{}
+
+	for _, row := range rows {
+		for level := 0; level < len(columns); level += 2 {
+			if columns[level] >= len(row) || columns[level+1] >= len(row) {
+				break
+			}
+
+			code := strings.TrimSpace(row[columns[level]])
+			if seen[code] {
+				continue
+			}
+			seen[code] = true
+			expanded = append(expanded, []string{code, row[columns[level+1]]})
+		}
+	}
+
+	return expanded
+}
+
+// add agrega una fila del catálogo.
+func (c *Catalog) add(row []string) error {
+	code, name := strings.TrimSpace(row[0]), strings.TrimSpace(row[1])
+
+	names := []string{name}
+	if len(row) > 2 && row[2] != "" {
+		names = append(names, strings.Split(row[2], "|")...)
+	}
+
+	var parent string
+	var entry any
+
+	switch len(code) {
+	case 2:
+		province := &Province{Code: code, Name: name}
+		c.provinces = append(c.provinces, province)
+		c.provincesByCode[code] = province
+		entry = province
+	case 4:
+		parent = code[:2]
+		province, ok := c.provincesByCode[parent]
+		if !ok {
+			return fmt.Errorf("%w: el cantón %s no tiene provincia", ErrInvalidCatalog, code)
+		}
+
+		canton := &Canton{Code: code, Name: name, Province: province}
+		province.Cantons = append(province.Cantons, canton)
+		c.cantonsByCode[code] = canton
+		entry = canton
+	case 6:
+		parent = code[:4]
+		canton, ok := c.cantonsByCode[parent]
+		if !ok {
+			return fmt.Errorf("%w: la parroquia %s no tiene cantón", ErrInvalidCatalog, code)
+		}
+
+		parish := &Parish{Code: code, Name: name, Canton: canton}
+		canton.Parishes = append(canton.Parishes, parish)
+		c.parishesByCode[code] = parish
+		entry = parish
+	default:
+		return fmt.Errorf("%w: código %q", ErrInvalidCatalog, code)
+	}
+
+	for _, alias := range names {
+		key := parent + "|" + normalize(alias)
+		if _, exists := c.names[key]; !exists {
+			c.names[key] = entry
+		}
+	}
+
+	return nil
+}
+
+// Provinces devuelve las provincias del catálogo.
+func (c *Catalog) Provinces() []*Province {
+	return slices.Clone(c.provinces)
+}
+
+// Province busca una provincia por su código ("06") o su nombre ("CHIMBORAZO").
+func (c *Catalog) Province(value string) (*Province, bool) {
+	if province, ok := c.provincesByCode[strings.TrimSpace(value)]; ok {
+		return province, true
+	}
+
+	province, ok := c.names["|"+normalize(value)].(*Province)
+	return province, ok
+}
+
+// Canton busca un cantón por su código de 4 dígitos, o por su nombre dentro de una
+// provincia.
+func (c *Catalog) Canton(province *Province, value string) (*Canton, bool) {
+	if canton, ok := c.cantonsByCode[strings.TrimSpace(value)]; ok && (province == nil || canton.Province == province) {
+		return canton, true
+	}
+
+	if province == nil {
+		return nil, false
+	}
+
+	canton, ok := c.names[province.Code+"|"+normalize(value)].(*Canton)
+	return canton, ok
+}
+
+// Parish busca una parroquia por su código de 6 dígitos, o por su nombre dentro de un
+// cantón.
+func (c *Catalog) Parish(canton *Canton, value string) (*Parish, bool) {
+	if parish, ok := c.parishesByCode[strings.TrimSpace(value)]; ok && (canton == nil || parish.Canton == canton) {
+		return parish, true
+	}
+
+	if canton == nil {
+		return nil, false
+	}
+
+	parish, ok := c.names[canton.Code+"|"+normalize(value)].(*Parish)
+	return parish, ok
+}
+
+// ProvinceOf devuelve la provincia en la que se emitió una cédula o un RUC, según sus dos
+// primeros dígitos. La identificación se valida con id.IsDNI o id.IsRUC.
+func (c *Catalog) ProvinceOf(identification string) (*Province, error) {
+	identification = strings.TrimSpace(identification)
+
+	var err error
+	if len(identification) == 13 {
+		err = id.IsRUC(identification)
+	} else {
+		err = id.IsDNI(identification)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	province, ok := c.provincesByCode[identification[:2]]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownProvince, identification[:2])
+	}
+
+	return province, nil
+}
+
+// isCode indica si el valor es un código numérico.
+func isCode(value string) bool {
+	return value != "" && !strings.ContainsFunc(value, func(r rune) bool { return r < '0' || r > '9' })
+}
+
+// accents reemplaza las vocales con tilde y la eñe por su letra base.
+var accents = strings.NewReplacer(
+	"Á", "A", "É", "E", "Í", "I", "Ó", "O", "Ú", "U", "Ü", "U", "Ñ", "N",
+	"á", "A", "é", "E", "í", "I", "ó", "O", "ú", "U", "ü", "U", "ñ", "N",
+)
+
+// normalize convierte un nombre a mayúsculas, sin tildes ni espacios repetidos, para
+// compararlo con el catálogo.
+func normalize(value string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(accents.Replace(value))), " ")
+}
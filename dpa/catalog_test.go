@@ -0,0 +1,134 @@
+package dpa
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pinzlab/sricore/id"
+)
+
+func TestDefault(t *testing.T) {
+	catalog := Default()
+
+	provinces := catalog.Provinces()
+	require.Len(t, provinces, 24)
+
+	cantons := 0
+	for i, province := range provinces {
+		assert.Equal(t, fmt.Sprintf("%02d", i+1), province.Code)
+		assert.NotEmpty(t, province.Cantons, province.Name)
+		for _, canton := range province.Cantons {
+			assert.Equal(t, province.Code, canton.Code[:2])
+			assert.Same(t, province, canton.Province)
+		}
+		cantons += len(province.Cantons)
+	}
+	assert.Equal(t, 221, cantons)
+}
+
+func TestCatalog_Lookup(t *testing.T) {
+	catalog := Default()
+
+	province, ok := catalog.Province("06")
+	require.True(t, ok)
+	assert.Equal(t, "CHIMBORAZO", province.Name)
+
+	province, ok = catalog.Province(" cañar ")
+	require.True(t, ok)
+	assert.Equal(t, "03", province.Code)
+
+	province, ok = catalog.Province("Santo Domingo")
+	require.True(t, ok)
+	assert.Equal(t, "23", province.Code)
+
+	_, ok = catalog.Province("25")
+	assert.False(t, ok)
+
+	canton, ok := catalog.Canton(province, "LA CONCORDIA")
+	require.True(t, ok)
+	assert.Equal(t, "2302", canton.Code)
+
+	// El mismo nombre de cantón existe en varias provincias.
+	carchi, _ := catalog.Province("CARCHI")
+	canton, ok = catalog.Canton(carchi, "Bolívar")
+	require.True(t, ok)
+	assert.Equal(t, "0402", canton.Code)
+
+	canton, ok = catalog.Canton(nil, "1701")
+	require.True(t, ok)
+	assert.Equal(t, "QUITO", canton.Name)
+
+	_, ok = catalog.Canton(carchi, "1701")
+	assert.False(t, ok)
+}
+
+func TestCatalog_ProvinceOf(t *testing.T) {
+	catalog := Default()
+
+	province, err := catalog.ProvinceOf("0601234560")
+	require.NoError(t, err)
+	assert.Equal(t, "CHIMBORAZO", province.Name)
+
+	province, err = catalog.ProvinceOf("1710034065001")
+	require.NoError(t, err)
+	assert.Equal(t, "PICHINCHA", province.Name)
+
+	_, err = catalog.ProvinceOf("0601234561")
+	assert.Equal(t, id.IsDNI("0601234561"), err)
+}
+
+func TestLoadCatalog(t *testing.T) {
+	catalog, err := LoadCatalog(strings.NewReader(
+		"CODIGO;NOMBRE\n" +
+			"170150;IÑAQUITO\n" +
+			"17;PICHINCHA\n" +
+			"1701;QUITO\n" +
+			"170101;BELISARIO QUEVEDO\n"))
+	require.NoError(t, err)
+
+	province, _ := catalog.Province("17")
+	canton, ok := catalog.Canton(province, "QUITO")
+	require.True(t, ok)
+	require.Len(t, canton.Parishes, 2)
+
+	parish, ok := catalog.Parish(canton, "Iñaquito")
+	require.True(t, ok)
+	assert.Equal(t, "170150", parish.Code)
+
+	_, err = LoadCatalog(strings.NewReader("1701;QUITO\n"))
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+
+	_, err = LoadCatalog(strings.NewReader("CODIGO;NOMBRE\n"))
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+
+	_, err = LoadCatalog(strings.NewReader("123;X\n"))
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+}
+
+func TestLoadCatalog_INEC(t *testing.T) {
+	catalog, err := LoadCatalog(strings.NewReader("\ufeff" +
+		"DPA_PROVIN,DPA_DESPRO,DPA_CANTON,DPA_DESCAN,DPA_PARROQ,DPA_DESPAR\n" +
+		"06,CHIMBORAZO,0601,RIOBAMBA,060150,RIOBAMBA\n" +
+		"06,CHIMBORAZO,0601,RIOBAMBA,060156,LICÁN\n" +
+		"06,CHIMBORAZO,0602,ALAUSI,060250,ALAUSI\n"))
+	require.NoError(t, err)
+
+	provinces := catalog.Provinces()
+	require.Len(t, provinces, 1)
+	require.Len(t, provinces[0].Cantons, 2)
+
+	canton, ok := catalog.Canton(provinces[0], "RIOBAMBA")
+	require.True(t, ok)
+	require.Len(t, canton.Parishes, 2)
+
+	parish, ok := catalog.Parish(canton, "Lican")
+	require.True(t, ok)
+	assert.Equal(t, "060156", parish.Code)
+
+	_, err = LoadCatalog(strings.NewReader("DPA_PROVIN;DPA_DESPRO;DPA_CANTON;DPA_DESCAN;DPA_PARROQ;DPA_DESPAR\n"))
+	assert.ErrorIs(t, err, ErrInvalidCatalog)
+}
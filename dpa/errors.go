@@ -0,0 +1,12 @@
+package dpa
+
+import "errors"
+
+var (
+	ErrInvalidCatalog  = errors.New("El catálogo no tiene el formato CODIGO;NOMBRE de la DPA del INEC")
+	ErrInvalidAddress  = errors.New("La dirección no tiene el formato PROVINCIA / CANTÓN / PARROQUIA")
+	ErrUnknownProvince = errors.New("La provincia no existe en el catálogo de la DPA")
+	ErrUnknownCanton   = errors.New("El cantón no existe en la provincia indicada")
+	ErrUnknownParish   = errors.New("La parroquia no existe en el cantón indicado")
+	ErrNoParishes      = errors.New("El catálogo no incluye las parroquias del cantón indicado")
+)
@@ -0,0 +1,323 @@
+package emit
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pinzlab/sricore/id"
+	"github.com/pinzlab/sricore/sri"
+	"github.com/pinzlab/sricore/ws"
+	"github.com/pinzlab/sricore/xades"
+)
+
+// State es una etapa del flujo de emisión de un comprobante.
+type State string
+
+const (
+	// StateKeyGenerated indica que se generó la clave de acceso.
+	StateKeyGenerated State = "CLAVE GENERADA"
+
+	// StateSigned indica que el XML del comprobante fue generado y firmado.
+	StateSigned State = "FIRMADO"
+
+	// StateReceived indica que el SRI recibió el comprobante.
+	StateReceived State = "RECIBIDA"
+
+	// StateAlreadyReceived indica que la clave de acceso ya estaba registrada en el SRI.
+	StateAlreadyReceived State = "CLAVE REGISTRADA"
+
+	// StateRetrying indica un error transitorio tras el cual se reintenta la operación.
+	StateRetrying State = "REINTENTO"
+
+	// StateReturned indica que el SRI devolvió el comprobante en la recepción.
+	StateReturned State = "DEVUELTA"
+
+	// StateProcessing indica que el comprobante sigue en procesamiento en el SRI.
+	StateProcessing State = "EN PROCESAMIENTO"
+
+	// StateAuthorized indica que el comprobante fue autorizado.
+	StateAuthorized State = "AUTORIZADO"
+
+	// StateRejected indica que el comprobante no fue autorizado.
+	StateRejected State = "NO AUTORIZADO"
+)
+
+// Transition es un cambio de estado registrado durante la emisión.
+type Transition struct {
+	// State: Estado alcanzado.
+	State State
+
+	// At: Momento en el que se registró el estado.
+	At time.Time
+
+	// Messages: Mensajes del SRI asociados al estado (si aplica).
+	Messages []*ws.Message
+
+	// Err: Error que provocó el estado (si aplica).
+	Err error
+}
+
+// Result es el resultado de la emisión de un comprobante.
+type Result struct {
+	// AccessKey: Clave de acceso de 49 dígitos del comprobante.
+	AccessKey string
+
+	// Signed: XML firmado enviado al SRI.
+	Signed []byte
+
+	// Authorization: Autorización del comprobante, o nil si no fue autorizado.
+	Authorization *ws.Authorization
+
+	// XML: Archivo <autorizacion> que se entrega al comprador.
+	XML []byte
+
+	// History: Estados por los que pasó el comprobante, en orden.
+	History []Transition
+}
+
+// record agrega un estado al historial.
+func (r *Result) record(state State, messages []*ws.Message, err error) {
+	r.History = append(r.History, Transition{State: state, At: time.Now(), Messages: messages, Err: err})
+}
+
+// Backoff define los reintentos de recepción y las consultas de autorización.
+type Backoff struct {
+	// Initial: Espera antes del primer reintento.
+	Initial time.Duration
+
+	// Max: Espera máxima entre reintentos.
+	Max time.Duration
+
+	// Multiplier: Factor por el que crece la espera tras cada reintento.
+	Multiplier float64
+
+	// Attempts: Número máximo de intentos de cada operación.
+	Attempts int
+}
+
+// DefaultBackoff son los reintentos usados por NewEmitter.
+var DefaultBackoff = Backoff{
+	Initial:    2 * time.Second,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+	Attempts:   10,
+}
+
+// delay devuelve la espera antes del reintento indicado (desde 0).
+func (b Backoff) delay(retry int) time.Duration {
+	delay := float64(b.Initial)
+	for range retry {
+		delay *= b.Multiplier
+		if b.Max > 0 && delay >= float64(b.Max) {
+			return b.Max
+		}
+	}
+
+	return time.Duration(delay)
+}
+
+// wait espera el reintento indicado o hasta que el contexto se cancele.
+func (b Backoff) wait(ctx context.Context, retry int) error {
+	timer := time.NewTimer(b.delay(retry))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Emitter ejecuta el flujo de emisión de comprobantes: generación de la clave de
+// acceso, XML, firma, recepción y autorización. Cada etapa puede reemplazarse.
+//
+// Establishments es opcional; si se asigna, el establecimiento del comprobante se valida
+// antes de generar la clave de acceso.
+type Emitter struct {
+	Keys           KeyGenerator
+	Marshaler      Marshaler
+	Signer         DocumentSigner
+	Receiver       Receiver
+	Authorizer     Authorizer
+	Establishments EstablishmentChecker
+	Backoff        Backoff
+}
+
+// NewEmitter crea un emisor que firma con XAdES-BES y envía los comprobantes al SRI.
+func NewEmitter(signer xades.Signer, service *ws.SRIOnline) *Emitter {
+	return &Emitter{
+		Keys:       RandomKeyGenerator{},
+		Marshaler:  XMLMarshaler{},
+		Signer:     XAdESSigner{Signer: signer},
+		Receiver:   service,
+		Authorizer: service,
+		Backoff:    DefaultBackoff,
+	}
+}
+
+// Emit emite un comprobante y espera su autorización.
+//
+// Si la clave de acceso ya estaba registrada (mensaje 43) o en procesamiento
+// (mensaje 70) se consulta directamente su autorización, por lo que volver a emitir
+// el mismo comprobante es seguro. El resultado se devuelve también junto con un error
+// para conservar el historial de estados.
+func (e *Emitter) Emit(ctx context.Context, voucher Voucher) (*Result, error) {
+	result := &Result{}
+
+	key := voucher.AccessKey()
+	if key == nil {
+		return result, ErrInvalidVoucher
+	}
+
+	if e.Establishments != nil {
+		if err := id.IsRUC(key.RUC); err != nil {
+			return result, fmt.Errorf("%w: %w", ErrInvalidVoucher, err)
+		}
+
+		// Solo los errores del establecimiento invalidan el comprobante; los errores de
+		// la consulta al catastro se devuelven sin cambios.
+		if _, err := e.Establishments.ValidateAccessKey(ctx, key); err != nil {
+			if invalidEstablishment(err) {
+				return result, fmt.Errorf("%w: %w", ErrInvalidVoucher, err)
+			}
+			return result, err
+		}
+	}
+
+	accessKey, err := e.Keys.Generate(key)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidVoucher, err)
+	}
+	result.AccessKey = accessKey
+	result.record(StateKeyGenerated, nil, nil)
+
+	doc, err := e.Marshaler.Marshal(voucher)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrMarshalVoucher, err)
+	}
+
+	result.Signed, err = e.Signer.Sign(ctx, doc)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrSignVoucher, err)
+	}
+	result.record(StateSigned, nil, nil)
+
+	if err := e.receive(ctx, result, key.Env); err != nil {
+		return result, err
+	}
+
+	return result, e.authorize(ctx, result)
+}
+
+// receive envía el comprobante firmado a recepción, reintentando los errores transitorios.
+func (e *Emitter) receive(ctx context.Context, result *Result, env sri.EnvType) error {
+	for attempt := 0; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		res, err := e.Receiver.ValidateVoucherContext(ctx, env, result.Signed)
+
+		var messages []*ws.Message
+		if err == nil {
+			messages = res.Messages()
+			err = res.Err()
+		}
+
+		switch {
+		case err == nil:
+			result.record(StateReceived, messages, nil)
+			return nil
+		case ws.IsAlreadyDone(err):
+			result.record(StateAlreadyReceived, messages, err)
+			return nil
+		case ws.IsRetryable(err) && errors.Is(err, ws.ErrAccessKeyProcessing):
+			result.record(StateProcessing, messages, err)
+			return nil
+		case !transient(err):
+			if res != nil {
+				result.record(StateReturned, messages, err)
+				return fmt.Errorf("%w: %w", ErrVoucherReturned, err)
+			}
+			return err
+		case attempt+1 >= e.Backoff.Attempts:
+			return err
+		}
+
+		result.record(StateRetrying, messages, err)
+		if err := e.Backoff.wait(ctx, attempt); err != nil {
+			return err
+		}
+	}
+}
+
+// authorize consulta la autorización del comprobante hasta que deje de estar en
+// procesamiento o se agoten los intentos.
+func (e *Emitter) authorize(ctx context.Context, result *Result) error {
+	for attempt := 0; attempt < e.Backoff.Attempts; attempt++ {
+		if attempt > 0 {
+			if err := e.Backoff.wait(ctx, attempt-1); err != nil {
+				return err
+			}
+		} else if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		res, err := e.Authorizer.AuthorizeVoucherContext(ctx, result.AccessKey)
+		if err != nil {
+			if !transient(err) {
+				return err
+			}
+
+			result.record(StateRetrying, nil, err)
+			continue
+		}
+
+		if authorization := res.Authorized(); authorization != nil {
+			var buf bytes.Buffer
+			if err := authorization.WriteXML(&buf); err != nil {
+				return err
+			}
+
+			result.Authorization = authorization
+			result.XML = buf.Bytes()
+			result.record(StateAuthorized, authorization.Messages, nil)
+			return nil
+		}
+
+		latest := res.Latest()
+		if latest == nil {
+			result.record(StateProcessing, nil, nil)
+			continue
+		}
+
+		if err := latest.Err(); err != nil && !ws.IsRetryable(err) {
+			result.record(StateRejected, latest.Messages, err)
+			return fmt.Errorf("%w: %w", ErrVoucherRejected, err)
+		}
+
+		result.record(StateProcessing, latest.Messages, nil)
+	}
+
+	return ErrAuthorizationPending
+}
+
+// transient indica si un error de comunicación con el SRI puede reintentarse.
+func transient(err error) bool {
+	return ws.IsRetryable(err) ||
+		errors.Is(err, ws.ErrHTTPRequest) ||
+		errors.Is(err, ws.ErrHTTPStatus) ||
+		errors.Is(err, ws.ErrReadBody) ||
+		errors.Is(err, ws.ErrSOAPFault)
+}
+
+// invalidEstablishment indica si el error se debe a que el establecimiento de la clave
+// de acceso no es válido, no está registrado o no está abierto.
+func invalidEstablishment(err error) bool {
+	return errors.Is(err, ws.ErrInvalidEstablishment) || errors.Is(err, ws.ErrContributorNotFound) ||
+		errors.Is(err, ws.ErrEstablishmentNotFound) || errors.Is(err, ws.ErrEstablishmentNotOpen)
+}
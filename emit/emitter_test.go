@@ -0,0 +1,282 @@
+package emit
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/pinzlab/sricore/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVoucher es un comprobante mínimo para las pruebas.
+type testVoucher struct {
+	XMLName xml.Name      `xml:"factura"`
+	ID      string        `xml:"id,attr"`
+	Key     sri.AccessKey `xml:"infoTributaria>claveAcceso"`
+}
+
+func (v *testVoucher) AccessKey() *sri.AccessKey {
+	return &v.Key
+}
+
+func newTestVoucher() *testVoucher {
+	return &testVoucher{
+		ID: "comprobante",
+		Key: sri.AccessKey{
+			Date:          time.Date(2020, time.February, 20, 0, 0, 0, 0, time.UTC),
+			VoucherType:   sri.Invoice,
+			RUC:           "0690000512001",
+			Env:           sri.EnvTest,
+			Establishment: "001",
+			EmissionPoint: "001",
+			Sequential:    "000000001",
+		},
+	}
+}
+
+// stubSigner devuelve el documento sin modificarlo.
+type stubSigner struct{}
+
+func (stubSigner) Sign(ctx context.Context, doc []byte) ([]byte, error) {
+	return doc, ctx.Err()
+}
+
+// blockingSigner espera hasta que el contexto se cancele, como un HSM que no responde.
+type blockingSigner struct{}
+
+func (blockingSigner) Sign(ctx context.Context, doc []byte) ([]byte, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// stubSRI responde con las respuestas programadas, en orden.
+type stubSRI struct {
+	receptions     []*ws.ReceptionResponse
+	authorizations []*ws.AuthorizationResponse
+	errs           []error
+	received       [][]byte
+	polls          int
+}
+
+func (s *stubSRI) ValidateVoucherContext(ctx context.Context, env sri.EnvType, signed []byte) (*ws.ReceptionResponse, error) {
+	s.received = append(s.received, signed)
+	if len(s.errs) > 0 {
+		err := s.errs[0]
+		s.errs = s.errs[1:]
+		return nil, err
+	}
+
+	res := s.receptions[0]
+	if len(s.receptions) > 1 {
+		s.receptions = s.receptions[1:]
+	}
+	return res, nil
+}
+
+func (s *stubSRI) AuthorizeVoucherContext(ctx context.Context, accessKey string) (*ws.AuthorizationResponse, error) {
+	s.polls++
+	res := s.authorizations[0]
+	if len(s.authorizations) > 1 {
+		s.authorizations = s.authorizations[1:]
+	}
+	return res, nil
+}
+
+func newTestEmitter(service *stubSRI) *Emitter {
+	return &Emitter{
+		Keys:       RandomKeyGenerator{},
+		Marshaler:  XMLMarshaler{},
+		Signer:     stubSigner{},
+		Receiver:   service,
+		Authorizer: service,
+		Backoff:    Backoff{Initial: time.Millisecond, Max: 2 * time.Millisecond, Multiplier: 2, Attempts: 3},
+	}
+}
+
+func returned(id string) *ws.ReceptionResponse {
+	return &ws.ReceptionResponse{
+		Status:   ws.ReceptionReturned,
+		Vouchers: []*ws.ReceivedVoucher{{Messages: []*ws.Message{{ID: id, Type: ws.MessageError}}}},
+	}
+}
+
+var (
+	received   = &ws.ReceptionResponse{Status: ws.ReceptionReceived}
+	processing = &ws.AuthorizationResponse{}
+	authorized = &ws.AuthorizationResponse{Count: 1, Authorizations: []*ws.Authorization{{
+		Status:  ws.AuthorizationAuthorized,
+		Voucher: `<factura id="comprobante"></factura>`,
+	}}}
+)
+
+func states(result *Result) []State {
+	var states []State
+	for _, transition := range result.History {
+		states = append(states, transition.State)
+	}
+
+	return states
+}
+
+func TestEmit(t *testing.T) {
+	service := &stubSRI{
+		receptions:     []*ws.ReceptionResponse{received},
+		authorizations: []*ws.AuthorizationResponse{processing, authorized},
+	}
+	voucher := newTestVoucher()
+
+	result, err := newTestEmitter(service).Emit(context.Background(), voucher)
+	require.NoError(t, err)
+
+	assert.Len(t, voucher.Key.Code, 8)
+	assert.NoError(t, sri.CheckAccessKey(result.AccessKey))
+	assert.Contains(t, string(result.Signed), "<claveAcceso>"+result.AccessKey+"</claveAcceso>")
+	assert.Equal(t, []State{StateKeyGenerated, StateSigned, StateReceived, StateProcessing, StateAuthorized}, states(result))
+	assert.Equal(t, ws.AuthorizationAuthorized, result.Authorization.Status)
+	assert.Contains(t, string(result.XML), "<autorizacion>")
+	assert.Equal(t, 2, service.polls)
+}
+
+// stubEstablishments responde siempre con el mismo error.
+type stubEstablishments struct {
+	err error
+}
+
+func (s stubEstablishments) ValidateAccessKey(ctx context.Context, key *sri.AccessKey) (*ws.Establishment, error) {
+	return nil, s.err
+}
+
+func TestEmit_ClosedEstablishment(t *testing.T) {
+	service := &stubSRI{}
+	emitter := newTestEmitter(service)
+	emitter.Establishments = stubEstablishments{err: ws.ErrEstablishmentNotOpen}
+
+	result, err := emitter.Emit(context.Background(), newTestVoucher())
+	assert.ErrorIs(t, err, ErrInvalidVoucher)
+	assert.ErrorIs(t, err, ws.ErrEstablishmentNotOpen)
+	assert.Empty(t, result.History)
+	assert.Empty(t, service.received)
+}
+
+func TestEmit_EstablishmentLookupUnavailable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	service := &stubSRI{}
+	emitter := newTestEmitter(service)
+	emitter.Establishments = ws.NewEstablishmentValidator(ws.NewSRIOnline(ws.WithBaseURL(server.URL)))
+
+	// Si el catastro no responde, el error no invalida el comprobante.
+	result, err := emitter.Emit(context.Background(), newTestVoucher())
+	assert.ErrorIs(t, err, ws.ErrHTTPStatus)
+	assert.NotErrorIs(t, err, ErrInvalidVoucher)
+	assert.Empty(t, result.History)
+	assert.Empty(t, service.received)
+
+	emitter.Establishments = stubEstablishments{err: ws.ErrCircuitOpen}
+	_, err = emitter.Emit(context.Background(), newTestVoucher())
+	assert.ErrorIs(t, err, ws.ErrCircuitOpen)
+	assert.NotErrorIs(t, err, ErrInvalidVoucher)
+
+	voucher := newTestVoucher()
+	voucher.Key.RUC = "0690000512000"
+	_, err = emitter.Emit(context.Background(), voucher)
+	assert.ErrorIs(t, err, ErrInvalidVoucher)
+}
+
+func TestEmit_AlreadyRegistered(t *testing.T) {
+	service := &stubSRI{
+		receptions:     []*ws.ReceptionResponse{returned("43")},
+		authorizations: []*ws.AuthorizationResponse{authorized},
+	}
+
+	result, err := newTestEmitter(service).Emit(context.Background(), newTestVoucher())
+	require.NoError(t, err)
+	assert.Equal(t, []State{StateKeyGenerated, StateSigned, StateAlreadyReceived, StateAuthorized}, states(result))
+	assert.ErrorIs(t, result.History[2].Err, ws.ErrAccessKeyRegistered)
+}
+
+func TestEmit_Retry(t *testing.T) {
+	service := &stubSRI{
+		errs:           []error{ws.ErrHTTPRequest},
+		receptions:     []*ws.ReceptionResponse{returned("50"), returned("70")},
+		authorizations: []*ws.AuthorizationResponse{authorized},
+	}
+
+	result, err := newTestEmitter(service).Emit(context.Background(), newTestVoucher())
+	require.NoError(t, err)
+	assert.Len(t, service.received, 3)
+	assert.Equal(t, []State{StateKeyGenerated, StateSigned, StateRetrying, StateRetrying, StateProcessing, StateAuthorized}, states(result))
+}
+
+func TestEmit_Returned(t *testing.T) {
+	service := &stubSRI{receptions: []*ws.ReceptionResponse{returned("35")}}
+
+	result, err := newTestEmitter(service).Emit(context.Background(), newTestVoucher())
+	assert.ErrorIs(t, err, ErrVoucherReturned)
+	assert.ErrorIs(t, err, ws.ErrXMLStructure)
+	assert.Equal(t, StateReturned, result.History[len(result.History)-1].State)
+	assert.Zero(t, service.polls)
+}
+
+func TestEmit_Rejected(t *testing.T) {
+	service := &stubSRI{
+		receptions: []*ws.ReceptionResponse{received},
+		authorizations: []*ws.AuthorizationResponse{{Count: 1, Authorizations: []*ws.Authorization{{
+			Status:   ws.AuthorizationRejected,
+			Messages: []*ws.Message{{ID: "39", Type: ws.MessageError}},
+		}}}},
+	}
+
+	result, err := newTestEmitter(service).Emit(context.Background(), newTestVoucher())
+	assert.ErrorIs(t, err, ErrVoucherRejected)
+	assert.ErrorIs(t, err, ws.ErrInvalidSignature)
+	assert.Nil(t, result.Authorization)
+}
+
+func TestEmit_Pending(t *testing.T) {
+	service := &stubSRI{
+		receptions:     []*ws.ReceptionResponse{received},
+		authorizations: []*ws.AuthorizationResponse{processing},
+	}
+
+	_, err := newTestEmitter(service).Emit(context.Background(), newTestVoucher())
+	assert.ErrorIs(t, err, ErrAuthorizationPending)
+	assert.Equal(t, 3, service.polls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = newTestEmitter(service).Emit(ctx, newTestVoucher())
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestEmit_SignTimeout(t *testing.T) {
+	service := &stubSRI{}
+	emitter := newTestEmitter(service)
+	emitter.Signer = blockingSigner{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	result, err := emitter.Emit(ctx, newTestVoucher())
+	assert.ErrorIs(t, err, ErrSignVoucher)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []State{StateKeyGenerated}, states(result))
+	assert.Empty(t, service.received)
+}
+
+func TestBackoff_Delay(t *testing.T) {
+	backoff := Backoff{Initial: time.Second, Max: 5 * time.Second, Multiplier: 2}
+	assert.Equal(t, time.Second, backoff.delay(0))
+	assert.Equal(t, 4*time.Second, backoff.delay(2))
+	assert.Equal(t, 5*time.Second, backoff.delay(3))
+}
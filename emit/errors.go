@@ -0,0 +1,12 @@
+package emit
+
+import "errors"
+
+var (
+	ErrInvalidVoucher       = errors.New("El comprobante no tiene una clave de acceso válida")
+	ErrMarshalVoucher       = errors.New("No se pudo generar el XML del comprobante")
+	ErrSignVoucher          = errors.New("No se pudo firmar el comprobante")
+	ErrVoucherReturned      = errors.New("El SRI devolvió el comprobante en la recepción")
+	ErrVoucherRejected      = errors.New("El SRI no autorizó el comprobante")
+	ErrAuthorizationPending = errors.New("El comprobante sigue en procesamiento en el SRI")
+)
@@ -0,0 +1,91 @@
+package emit
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"math/rand/v2"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/pinzlab/sricore/ws"
+	"github.com/pinzlab/sricore/xades"
+)
+
+// Voucher es un comprobante electrónico que puede emitirse.
+//
+// AccessKey devuelve un puntero a los datos de la clave de acceso del propio
+// comprobante (normalmente el campo infoTributaria>claveAcceso), de modo que el
+// código numérico generado quede registrado en el XML.
+type Voucher interface {
+	AccessKey() *sri.AccessKey
+}
+
+// KeyGenerator completa los datos de la clave de acceso y devuelve la clave de 49 dígitos.
+type KeyGenerator interface {
+	Generate(key *sri.AccessKey) (string, error)
+}
+
+// Marshaler genera el XML del comprobante.
+type Marshaler interface {
+	Marshal(voucher Voucher) ([]byte, error)
+}
+
+// DocumentSigner firma el XML del comprobante. La cancelación o el plazo del contexto
+// deben interrumpir la firma.
+type DocumentSigner interface {
+	Sign(ctx context.Context, doc []byte) ([]byte, error)
+}
+
+// Receiver envía un comprobante firmado al servicio de recepción del SRI.
+// *ws.SRIOnline implementa esta interfaz.
+type Receiver interface {
+	ValidateVoucherContext(ctx context.Context, env sri.EnvType, signed []byte) (*ws.ReceptionResponse, error)
+}
+
+// Authorizer consulta el servicio de autorización del SRI.
+// *ws.SRIOnline implementa esta interfaz.
+type Authorizer interface {
+	AuthorizeVoucherContext(ctx context.Context, accessKey string) (*ws.AuthorizationResponse, error)
+}
+
+// EstablishmentChecker verifica el establecimiento del comprobante antes de emitirlo.
+// *ws.EstablishmentValidator implementa esta interfaz.
+type EstablishmentChecker interface {
+	ValidateAccessKey(ctx context.Context, key *sri.AccessKey) (*ws.Establishment, error)
+}
+
+// RandomKeyGenerator genera el código numérico de 8 dígitos cuando la clave de acceso
+// no lo tiene y calcula el dígito verificador.
+type RandomKeyGenerator struct{}
+
+// Generate implementa KeyGenerator.
+func (RandomKeyGenerator) Generate(key *sri.AccessKey) (string, error) {
+	if key.Code == "" {
+		key.Code = fmt.Sprintf("%08d", rand.IntN(100000000))
+	}
+
+	return key.Generate()
+}
+
+// XMLMarshaler genera el XML del comprobante con encoding/xml, incluyendo la cabecera.
+type XMLMarshaler struct{}
+
+// Marshal implementa Marshaler.
+func (XMLMarshaler) Marshal(voucher Voucher) ([]byte, error) {
+	data, err := xml.Marshal(voucher)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), data...), nil
+}
+
+// XAdESSigner firma el comprobante con XAdES-BES usando el firmante indicado.
+type XAdESSigner struct {
+	Signer xades.Signer
+}
+
+// Sign implementa DocumentSigner.
+func (s XAdESSigner) Sign(ctx context.Context, doc []byte) ([]byte, error) {
+	return xades.SignContext(ctx, doc, s.Signer)
+}
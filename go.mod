@@ -2,10 +2,14 @@ module github.com/pinzlab/sricore
 
 go 1.23.4
 
-require github.com/stretchr/testify v1.10.0
+require (
+	github.com/stretchr/testify v1.10.0
+	software.sslmate.com/src/go-pkcs12 v0.7.3
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/crypto v0.11.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -182,6 +182,27 @@ func (ak *AccessKey) FromString(accessKey string) error {
 	return nil
 }
 
+// CheckAccessKey verifica que una clave de acceso de 49 dígitos tenga un formato
+// válido y que su último dígito coincida con el dígito verificador calculado con
+// el algoritmo módulo 11.
+func CheckAccessKey(accessKey string) error {
+	var ak AccessKey
+	if err := ak.FromString(accessKey); err != nil {
+		return err
+	}
+
+	expected, err := ak.Generate()
+	if err != nil {
+		return err
+	}
+
+	if expected != accessKey {
+		return ErrAccessKeyCheckDigit
+	}
+
+	return nil
+}
+
 // UnmarshalXML implementa el deserializado personalizado para AccessKey.
 func (ak *AccessKey) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	var akString string
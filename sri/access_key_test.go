@@ -274,3 +274,21 @@ func TestAccessKeyMarshalXML(t *testing.T) {
 	expectedXML := "<AccessKey>2002202001179125123700120010010058149171234567817</AccessKey>"
 	assert.Equal(t, expectedXML, string(xmlData))
 }
+
+func TestCheckAccessKey(t *testing.T) {
+	tests := []struct {
+		accessKey string
+		err       error
+	}{
+		{accessKey: "2002202001179125123700120010010058149171234567817", err: nil},
+		{accessKey: "2002202001179125123700120010010058149171234567811", err: ErrAccessKeyCheckDigit},
+		{accessKey: "200220200117912512370012001001005814917123456781", err: ErrInvalidAccessKeyFormat},
+		{accessKey: "3002202001171404598400120010010001837471234567812", err: ErrInvalidAccessKeyDate},
+	}
+
+	for _, test := range tests {
+		t.Run(test.accessKey, func(t *testing.T) {
+			assert.Equal(t, test.err, CheckAccessKey(test.accessKey))
+		})
+	}
+}
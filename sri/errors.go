@@ -8,6 +8,7 @@ var (
 	ErrInvalidAccessKeyDate   = errors.New("Fecha inválida en clave de acceso")
 	ErrInvalidAccessKeyDigit  = errors.New("Error al calcular el dígito verificador de la clave de acceso")
 	ErrInvalidVoucherDate     = errors.New("Fecha inválida en formato SRI (esperado 02/01/2006)")
+	ErrAccessKeyCheckDigit    = errors.New("El dígito verificador de la clave de acceso no es válido")
 )
 
 // Mensajes con formato (tipo string)
@@ -0,0 +1,46 @@
+package sritest
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pinzlab/sricore/ws"
+)
+
+// existsRUC imita ConsolidadoContribuyente/existePorNumeroRuc.
+func (s *Server) existsRUC(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	_, ok := s.contributors[ruc(r)]
+	s.mu.Unlock()
+
+	writeJSON(w, ok)
+}
+
+// contributor imita ConsolidadoContribuyente/obtenerPorNumerosRuc, que acepta varios
+// RUC separados por comas.
+func (s *Server) contributor(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	contributors := []*ws.Contributor{}
+	for _, value := range strings.Split(ruc(r), ",") {
+		if fixture, ok := s.contributors[strings.TrimSpace(value)]; ok {
+			contributors = append(contributors, fixture.Contributor)
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, contributors)
+}
+
+// establishments imita Establecimiento/consultarPorNumeroRuc.
+func (s *Server) establishments(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	fixture, ok := s.contributors[ruc(r)]
+	s.mu.Unlock()
+
+	establishments := []*ws.Establishment{}
+	if ok {
+		establishments = append(establishments, fixture.Establishments...)
+	}
+
+	writeJSON(w, establishments)
+}
@@ -0,0 +1,293 @@
+// Package sritest proporciona un servidor local que imita los servicios web del SRI
+// (catastro de contribuyentes, matriculación vehicular, RecepcionComprobantesOffline y
+// AutorizacionComprobantesOffline) para pruebas de integración sin conexión.
+package sritest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pinzlab/sricore/ws"
+)
+
+const (
+	catastroPath = "/sri-catastro-sujeto-servicio-internet/rest"
+	vehiclePath  = "/sri-matriculacion-vehicular-recaudacion-servicio-internet/rest"
+	vouchersPath = "/comprobantes-electronicos-ws"
+)
+
+// Fixture son los datos de un contribuyente registrados en el servidor.
+type Fixture struct {
+	// Contributor: Información del contribuyente.
+	Contributor *ws.Contributor `json:"contribuyente"`
+
+	// Establishments: Establecimientos del contribuyente.
+	Establishments []*ws.Establishment `json:"establecimientos"`
+}
+
+// voucher es un comprobante recibido por el servidor.
+type voucher struct {
+	signed []byte
+	polls  int
+}
+
+// Server es un servidor HTTP que imita los servicios web del SRI.
+//
+// Las solicitudes hechas con el cliente devuelto por Client se redirigen al servidor
+// sin importar el host, por lo que el cliente de ws puede usarse sin cambios:
+//
+//	server := sritest.NewServer()
+//	defer server.Close()
+//
+//	service := ws.NewSRIOnline(ws.WithHTTPClient(server.Client()))
+//
+// Install hace lo mismo con http.DefaultTransport, para el código que no permite
+// indicar el cliente HTTP.
+type Server struct {
+	*httptest.Server
+
+	// transport es el http.DefaultTransport reemplazado por Install.
+	transport http.RoundTripper
+
+	mu           sync.Mutex
+	contributors map[string]*Fixture
+	vehicles     map[string]*vehicle
+	rejections   map[string][]*ws.Message
+	unauthorized map[string][]*ws.Message
+	delays       map[string]int
+	vouchers     map[string]*voucher
+	outage       int
+	requests     int
+}
+
+// NewServer inicia un servidor sin contribuyentes ni comprobantes registrados.
+func NewServer() *Server {
+	s := &Server{
+		contributors: map[string]*Fixture{},
+		vehicles:     map[string]*vehicle{},
+		rejections:   map[string][]*ws.Message{},
+		unauthorized: map[string][]*ws.Message{},
+		delays:       map[string]int{},
+		vouchers:     map[string]*voucher{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(catastroPath+"/ConsolidadoContribuyente/existePorNumeroRuc", s.existsRUC)
+	mux.HandleFunc(catastroPath+"/ConsolidadoContribuyente/obtenerPorNumerosRuc", s.contributor)
+	mux.HandleFunc(catastroPath+"/Establecimiento/consultarPorNumeroRuc", s.establishments)
+	mux.HandleFunc(vehiclePath+"/BaseVehiculo/obtenerPorNumeroPlacaOPorNumeroCampvOPorNumeroCpn", s.vehicleByPlate)
+	mux.HandleFunc(vehiclePath+"/ConsultaRubros/obtenerPorCodigoVehiculo", s.vehicleFees)
+	mux.HandleFunc(vouchersPath+"/RecepcionComprobantesOffline", s.reception)
+	mux.HandleFunc(vouchersPath+"/AutorizacionComprobantesOffline", s.authorization)
+
+	s.Server = httptest.NewServer(s.intercept(mux))
+
+	return s
+}
+
+// Client devuelve un cliente HTTP que envía al servidor todas las solicitudes,
+// conservando la ruta, la consulta y el host original en la cabecera Host.
+func (s *Server) Client() *http.Client {
+	return &http.Client{Transport: &redirect{target: s.Listener.Addr().String(), base: s.Server.Client().Transport}}
+}
+
+// Install redirige al servidor las solicitudes enviadas con http.DefaultTransport hasta
+// que se cierre el servidor. Las pruebas que lo usan no deben ejecutarse en paralelo.
+func (s *Server) Install() {
+	if s.transport == nil {
+		s.transport = http.DefaultTransport
+		http.DefaultTransport = s.Client().Transport
+	}
+}
+
+// Close restaura http.DefaultTransport y detiene el servidor.
+func (s *Server) Close() {
+	if s.transport != nil {
+		http.DefaultTransport = s.transport
+		s.transport = nil
+	}
+
+	s.Server.Close()
+}
+
+// redirect reescribe el destino de las solicitudes hacia el servidor de pruebas.
+type redirect struct {
+	target string
+	base   http.RoundTripper
+}
+
+// RoundTrip implementa http.RoundTripper.
+func (r *redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Host = req.URL.Host
+	req.URL.Scheme = "http"
+	req.URL.Host = r.target
+
+	return r.base.RoundTrip(req)
+}
+
+// AddContributor registra un contribuyente y sus establecimientos.
+func (s *Server) AddContributor(contributor *ws.Contributor, establishments ...*ws.Establishment) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.contributors[contributor.Ruc] = &Fixture{Contributor: contributor, Establishments: establishments}
+}
+
+// LoadFixtures registra los contribuyentes de un archivo JSON con una lista de Fixture.
+func (s *Server) LoadFixtures(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var fixtures []*Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return err
+	}
+
+	for _, fixture := range fixtures {
+		s.AddContributor(fixture.Contributor, fixture.Establishments...)
+	}
+
+	return nil
+}
+
+// Reject hace que la recepción del comprobante con la clave de acceso indicada se
+// devuelva (DEVUELTA) con los mensajes del catálogo indicados. Una clave vacía aplica
+// a todos los comprobantes sin una configuración propia.
+func (s *Server) Reject(accessKey string, ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rejections[accessKey] = messages(ids)
+}
+
+// RejectAuthorization hace que el comprobante sea recibido pero NO AUTORIZADO con los
+// mensajes del catálogo indicados. Una clave vacía aplica a todos los comprobantes.
+func (s *Server) RejectAuthorization(accessKey string, ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.unauthorized[accessKey] = messages(ids)
+}
+
+// DelayAuthorization hace que las primeras consultas de autorización del comprobante
+// respondan sin autorizaciones, como ocurre mientras está en procesamiento. Una clave
+// vacía aplica a todos los comprobantes.
+func (s *Server) DelayAuthorization(accessKey string, polls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.delays[accessKey] = polls
+}
+
+// Outage hace que las siguientes solicitudes respondan con el estado 503. Un número
+// negativo mantiene la interrupción hasta llamar a Restore.
+func (s *Server) Outage(requests int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.outage = requests
+}
+
+// Restore finaliza una interrupción iniciada con Outage.
+func (s *Server) Restore() {
+	s.Outage(0)
+}
+
+// Received devuelve el comprobante firmado recibido con la clave de acceso indicada.
+func (s *Server) Received(accessKey string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vouchers[accessKey]
+	if !ok {
+		return nil, false
+	}
+
+	return v.signed, true
+}
+
+// Polls devuelve el número de consultas de autorización de la clave de acceso indicada.
+func (s *Server) Polls(accessKey string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if v, ok := s.vouchers[accessKey]; ok {
+		return v.polls
+	}
+
+	return 0
+}
+
+// Requests devuelve el número total de solicitudes recibidas, incluidas las fallidas.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests
+}
+
+// intercept cuenta las solicitudes y simula las interrupciones del servicio.
+func (s *Server) intercept(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		down := s.outage != 0
+		if s.outage > 0 {
+			s.outage--
+		}
+		s.mu.Unlock()
+
+		if down {
+			http.Error(w, "Service Unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// lookup devuelve la configuración de la clave de acceso, o la configuración general.
+func lookup[T any](values map[string]T, accessKey string) (T, bool) {
+	if value, ok := values[accessKey]; ok {
+		return value, true
+	}
+
+	value, ok := values[""]
+	return value, ok
+}
+
+// messages construye los mensajes de error a partir de identificadores del catálogo.
+func messages(ids []string) []*ws.Message {
+	var result []*ws.Message
+	for _, id := range ids {
+		message := &ws.Message{ID: id, Type: ws.MessageError}
+		if err, ok := ws.LookupVoucherError(id); ok {
+			message.Message = err.Message
+		}
+		result = append(result, message)
+	}
+
+	return result
+}
+
+// writeJSON responde con el valor indicado en formato JSON.
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+// ruc obtiene el RUC de la consulta, que el SRI recibe como numeroRuc o ruc.
+func ruc(r *http.Request) string {
+	if value := r.URL.Query().Get("numeroRuc"); value != "" {
+		return strings.TrimSpace(value)
+	}
+
+	return strings.TrimSpace(r.URL.Query().Get("ruc"))
+}
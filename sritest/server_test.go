@@ -0,0 +1,150 @@
+package sritest
+
+import (
+	"context"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/emit"
+	"github.com/pinzlab/sricore/sri"
+	"github.com/pinzlab/sricore/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testVoucher es un comprobante mínimo para las pruebas.
+type testVoucher struct {
+	XMLName xml.Name      `xml:"factura"`
+	ID      string        `xml:"id,attr"`
+	Key     sri.AccessKey `xml:"infoTributaria>claveAcceso"`
+}
+
+func (v *testVoucher) AccessKey() *sri.AccessKey {
+	return &v.Key
+}
+
+func newTestVoucher(sequential string) *testVoucher {
+	return &testVoucher{
+		ID: "comprobante",
+		Key: sri.AccessKey{
+			Date:          time.Date(2020, time.February, 20, 0, 0, 0, 0, time.UTC),
+			VoucherType:   sri.Invoice,
+			RUC:           "0690000512001",
+			Env:           sri.EnvTest,
+			Establishment: "001",
+			EmissionPoint: "001",
+			Sequential:    sequential,
+			Code:          "12345678",
+		},
+	}
+}
+
+// unsigned devuelve el documento sin firmar; el servidor no verifica la firma.
+type unsigned struct{}
+
+func (unsigned) Sign(ctx context.Context, doc []byte) ([]byte, error) {
+	return doc, nil
+}
+
+func newTestEmitter(server *Server) *emit.Emitter {
+	service := ws.NewSRIOnline(ws.WithHTTPClient(server.Client()))
+
+	emitter := emit.NewEmitter(nil, service)
+	emitter.Signer = unsigned{}
+	emitter.Backoff = emit.Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1, Attempts: 5}
+
+	return emitter
+}
+
+func TestServer_LoadFixtures(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	require.NoError(t, server.LoadFixtures("testdata/contributors.json"))
+
+	service := ws.NewSRIOnline(ws.WithHTTPClient(server.Client()))
+
+	contributors, err := service.GetContributors("0690000512001")
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+	assert.Equal(t, "EMPRESA ELECTRICA RIOBAMBA SA", contributors[0].BusinessName)
+	assert.True(t, bool(contributors[0].SpecialTaxpayer))
+
+	establishments, err := service.GetEstablishments("0690000512001")
+	require.NoError(t, err)
+	require.Len(t, establishments, 1)
+	assert.Equal(t, "ABIERTO", establishments[0].Status)
+
+	assert.Error(t, server.LoadFixtures("testdata/missing.json"))
+}
+
+func TestServer_Emit(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.DelayAuthorization("", 2)
+
+	result, err := newTestEmitter(server).Emit(context.Background(), newTestVoucher("000000001"))
+	require.NoError(t, err)
+	assert.Equal(t, ws.AuthorizationAuthorized, result.Authorization.Status)
+	assert.Equal(t, "PRUEBAS", result.Authorization.Env)
+	assert.Equal(t, 3, server.Polls(result.AccessKey))
+
+	signed, ok := server.Received(result.AccessKey)
+	require.True(t, ok)
+	assert.Equal(t, string(signed), result.Authorization.Voucher)
+
+	// El reenvío de la misma clave responde CLAVE ACCESO REGISTRADA.
+	result, err = newTestEmitter(server).Emit(context.Background(), newTestVoucher("000000001"))
+	require.NoError(t, err)
+	assert.Equal(t, emit.StateAlreadyReceived, result.History[2].State)
+}
+
+func TestServer_Reject(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	voucher := newTestVoucher("000000002")
+	accessKey, err := voucher.Key.Generate()
+	require.NoError(t, err)
+
+	server.Reject(accessKey, "35", "52")
+
+	_, err = newTestEmitter(server).Emit(context.Background(), voucher)
+	assert.ErrorIs(t, err, emit.ErrVoucherReturned)
+	assert.ErrorIs(t, err, ws.ErrXMLStructure)
+	assert.ErrorIs(t, err, ws.ErrTotalsMismatch)
+
+	_, ok := server.Received(accessKey)
+	assert.False(t, ok)
+
+	server.RejectAuthorization("", "39")
+
+	_, err = newTestEmitter(server).Emit(context.Background(), newTestVoucher("000000003"))
+	assert.ErrorIs(t, err, emit.ErrVoucherRejected)
+	assert.ErrorIs(t, err, ws.ErrInvalidSignature)
+}
+
+func TestServer_Outage(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	server.Outage(2)
+
+	result, err := newTestEmitter(server).Emit(context.Background(), newTestVoucher("000000004"))
+	require.NoError(t, err)
+	assert.NotNil(t, result.Authorization)
+	assert.Equal(t, 4, server.Requests())
+
+	server.Outage(-1)
+
+	service := ws.NewSRIOnline(ws.WithHTTPClient(server.Client()))
+	_, err = service.CheckRUC("0690000512001")
+	assert.ErrorIs(t, err, ws.ErrHTTPStatus)
+
+	server.Restore()
+	exists, err := service.CheckRUC("0690000512001")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,64 @@
+package sritest
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pinzlab/sricore/ws"
+)
+
+// vehicle es un vehículo registrado en el servidor con sus valores pendientes.
+type vehicle struct {
+	data *ws.Vehicle
+	fees []*ws.VehicleFee
+}
+
+// AddVehicle registra un vehículo y sus valores pendientes de pago. El vehículo puede
+// consultarse por su placa o por su CAMV o CPN.
+func (s *Server) AddVehicle(data *ws.Vehicle, fees ...*ws.VehicleFee) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v := &vehicle{data: data, fees: fees}
+	for _, key := range []string{data.Plate, data.CAMV} {
+		if key != "" {
+			s.vehicles[strings.ToUpper(key)] = v
+		}
+	}
+}
+
+// vehicleByPlate imita BaseVehiculo/obtenerPorNumeroPlacaOPorNumeroCampvOPorNumeroCpn.
+// Un vehículo desconocido se responde con null.
+func (s *Server) vehicleByPlate(w http.ResponseWriter, r *http.Request) {
+	plate := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("numeroPlacaCampvCpn")))
+
+	s.mu.Lock()
+	v, ok := s.vehicles[plate]
+	s.mu.Unlock()
+
+	if !ok {
+		writeJSON(w, nil)
+		return
+	}
+
+	writeJSON(w, v.data)
+}
+
+// vehicleFees imita ConsultaRubros/obtenerPorCodigoVehiculo.
+func (s *Server) vehicleFees(w http.ResponseWriter, r *http.Request) {
+	code, _ := strconv.ParseInt(r.URL.Query().Get("codigoVehiculo"), 10, 64)
+
+	fees := []*ws.VehicleFee{}
+
+	s.mu.Lock()
+	for _, v := range s.vehicles {
+		if v.data.Code == code {
+			fees = append(fees, v.fees...)
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, fees)
+}
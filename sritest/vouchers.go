@@ -0,0 +1,204 @@
+package sritest
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/pinzlab/sricore/ws"
+)
+
+const (
+	receptionNamespace     = "http://ec.gob.sri.ws.recepcion"
+	authorizationNamespace = "http://ec.gob.sri.ws.autorizacion"
+)
+
+// soapRequest es el sobre SOAP de una solicitud a los servicios de comprobantes.
+type soapRequest struct {
+	Body struct {
+		Operation struct {
+			XML       string `xml:"xml"`
+			AccessKey string `xml:"claveAccesoComprobante"`
+		} `xml:",any"`
+	} `xml:"Body"`
+}
+
+// receptionResult es el contenido de validarComprobanteResponse.
+type receptionResult struct {
+	XMLName xml.Name `xml:"RespuestaRecepcionComprobante"`
+	ws.ReceptionResponse
+}
+
+// authorizationResult es el contenido de autorizacionComprobanteResponse.
+type authorizationResult struct {
+	XMLName xml.Name `xml:"RespuestaAutorizacionComprobante"`
+	ws.AuthorizationResponse
+}
+
+// reception imita la operación validarComprobante de RecepcionComprobantesOffline.
+func (s *Server) reception(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("wsdl") {
+		writeWSDL(w, receptionNamespace, "RecepcionComprobantesOfflineService")
+		return
+	}
+
+	var request soapRequest
+	if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeFault(w, err)
+		return
+	}
+
+	signed, err := base64.StdEncoding.DecodeString(request.Body.Operation.XML)
+	if err != nil || len(signed) == 0 {
+		writeSOAP(w, receptionNamespace, "validarComprobante", returned("", messages([]string{"49"})))
+		return
+	}
+
+	accessKey := accessKeyOf(signed)
+	if sri.CheckAccessKey(accessKey) != nil {
+		writeSOAP(w, receptionNamespace, "validarComprobante", returned(accessKey, messages([]string{"35"})))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if rejection, ok := lookup(s.rejections, accessKey); ok {
+		writeSOAP(w, receptionNamespace, "validarComprobante", returned(accessKey, rejection))
+		return
+	}
+
+	if _, ok := s.vouchers[accessKey]; ok {
+		writeSOAP(w, receptionNamespace, "validarComprobante", returned(accessKey, messages([]string{"43"})))
+		return
+	}
+
+	s.vouchers[accessKey] = &voucher{signed: signed}
+
+	writeSOAP(w, receptionNamespace, "validarComprobante", receptionResult{
+		ReceptionResponse: ws.ReceptionResponse{Status: ws.ReceptionReceived},
+	})
+}
+
+// authorization imita la operación autorizacionComprobante de AutorizacionComprobantesOffline.
+func (s *Server) authorization(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Has("wsdl") {
+		writeWSDL(w, authorizationNamespace, "AutorizacionComprobantesOfflineService")
+		return
+	}
+
+	var request soapRequest
+	if err := xml.NewDecoder(r.Body).Decode(&request); err != nil {
+		writeFault(w, err)
+		return
+	}
+
+	accessKey := request.Body.Operation.AccessKey
+	result := authorizationResult{AuthorizationResponse: ws.AuthorizationResponse{AccessKey: accessKey}}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	v, ok := s.vouchers[accessKey]
+	if !ok {
+		writeSOAP(w, authorizationNamespace, "autorizacionComprobante", result)
+		return
+	}
+
+	v.polls++
+	if delay, ok := lookup(s.delays, accessKey); ok && v.polls <= delay {
+		writeSOAP(w, authorizationNamespace, "autorizacionComprobante", result)
+		return
+	}
+
+	authorization := &ws.Authorization{
+		Status:  ws.AuthorizationAuthorized,
+		Number:  accessKey,
+		Date:    time.Now(),
+		Env:     "PRUEBAS",
+		Voucher: string(v.signed),
+	}
+
+	if accessKey[23:24] == string(sri.EnvProd) {
+		authorization.Env = "PRODUCCIÓN"
+	}
+
+	if rejection, ok := lookup(s.unauthorized, accessKey); ok {
+		authorization.Status = ws.AuthorizationRejected
+		authorization.Number = ""
+		authorization.Messages = rejection
+	}
+
+	result.Count = 1
+	result.Authorizations = []*ws.Authorization{authorization}
+
+	writeSOAP(w, authorizationNamespace, "autorizacionComprobante", result)
+}
+
+// returned construye una respuesta de recepción DEVUELTA.
+func returned(accessKey string, messages []*ws.Message) receptionResult {
+	return receptionResult{ReceptionResponse: ws.ReceptionResponse{
+		Status:   ws.ReceptionReturned,
+		Vouchers: []*ws.ReceivedVoucher{{AccessKey: accessKey, Messages: messages}},
+	}}
+}
+
+// accessKeyOf extrae la clave de acceso (infoTributaria>claveAcceso) del comprobante.
+func accessKeyOf(doc []byte) string {
+	decoder := xml.NewDecoder(bytes.NewReader(doc))
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+
+		if start, ok := token.(xml.StartElement); ok && start.Name.Local == "claveAcceso" {
+			var value string
+			if err := decoder.DecodeElement(&value, &start); err != nil {
+				return ""
+			}
+
+			return value
+		}
+	}
+}
+
+// writeSOAP responde con el sobre SOAP de la operación y el contenido indicado.
+func writeSOAP(w http.ResponseWriter, namespace, operation string, content any) {
+	data, err := xml.Marshal(content)
+	if err != nil {
+		writeFault(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+	_, _ = fmt.Fprintf(w, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>`+
+		`<ns2:%[2]sResponse xmlns:ns2="%[1]s">%[3]s</ns2:%[2]sResponse></soap:Body></soap:Envelope>`,
+		namespace, operation, data)
+}
+
+// writeWSDL responde con una descripción WSDL mínima del servicio, usada por
+// ws.HealthMonitor para comprobar su disponibilidad.
+func writeWSDL(w http.ResponseWriter, namespace, service string) {
+	w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+	_, _ = fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>`+
+		`<wsdl:definitions xmlns:wsdl="http://schemas.xmlsoap.org/wsdl/" name="%[2]s" targetNamespace="%[1]s">`+
+		`<wsdl:service name="%[2]s"/></wsdl:definitions>`, namespace, service)
+}
+
+// writeFault responde con un SOAP Fault y el estado 500.
+func writeFault(w http.ResponseWriter, err error) {
+	w.Header().Set("Content-Type", "text/xml;charset=UTF-8")
+	w.WriteHeader(http.StatusInternalServerError)
+
+	var message bytes.Buffer
+	_ = xml.EscapeText(&message, []byte(err.Error()))
+
+	_, _ = fmt.Fprintf(w, `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>`+
+		`<soap:Fault><faultcode>soap:Client</faultcode><faultstring>%s</faultstring></soap:Fault>`+
+		`</soap:Body></soap:Envelope>`, message.String())
+}
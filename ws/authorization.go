@@ -0,0 +1,231 @@
+package ws
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+)
+
+const authorizationNamespace = "http://ec.gob.sri.ws.autorizacion"
+
+// AuthorizationStatus es el estado de autorización de un comprobante.
+type AuthorizationStatus string
+
+const (
+	// AuthorizationAuthorized indica que el comprobante fue autorizado.
+	AuthorizationAuthorized AuthorizationStatus = "AUTORIZADO"
+
+	// AuthorizationRejected indica que el comprobante no fue autorizado.
+	AuthorizationRejected AuthorizationStatus = "NO AUTORIZADO"
+
+	// AuthorizationInProcess indica que el comprobante aún está en procesamiento.
+	AuthorizationInProcess AuthorizationStatus = "EN PROCESO"
+)
+
+// authorizationDateFormats son los formatos de fecha usados por el SRI en fechaAutorizacion.
+var authorizationDateFormats = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"02/01/2006 15:04:05",
+}
+
+// Authorization es un intento de autorización de un comprobante.
+type Authorization struct {
+	// Status: Estado de la autorización.
+	Status AuthorizationStatus `xml:"estado"`
+
+	// Number: Número de autorización; coincide con la clave de acceso en emisión offline.
+	Number string `xml:"numeroAutorizacion"`
+
+	// Date: Fecha y hora de autorización.
+	Date time.Time `xml:"-"`
+
+	// RawDate: fechaAutorizacion tal como se recibió cuando no tiene un formato reconocido;
+	// en ese caso Date queda en cero y la respuesta se notifica con OnSchemaDrift.
+	RawDate string `xml:"-"`
+
+	// Env: Ambiente en el que se procesó el comprobante (PRUEBAS o PRODUCCIÓN).
+	Env string `xml:"ambiente"`
+
+	// Voucher: XML del comprobante autorizado, tal como se recibió en la sección CDATA.
+	Voucher string `xml:"comprobante"`
+
+	// Messages: Mensajes asociados a la autorización.
+	Messages []*Message `xml:"mensajes>mensaje"`
+}
+
+// authorizationXML es la representación XML de Authorization.
+type authorizationXML struct {
+	XMLName  xml.Name            `xml:"autorizacion"`
+	Status   AuthorizationStatus `xml:"estado"`
+	Number   string              `xml:"numeroAutorizacion"`
+	Date     string              `xml:"fechaAutorizacion"`
+	Env      string              `xml:"ambiente"`
+	Voucher  cdata               `xml:"comprobante"`
+	Messages []*Message          `xml:"mensajes>mensaje"`
+}
+
+// cdata es un texto que se serializa dentro de una sección CDATA.
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+// UnmarshalXML implementa el deserializado personalizado para Authorization.
+func (a *Authorization) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var raw authorizationXML
+	if err := d.DecodeElement(&raw, &start); err != nil {
+		return err
+	}
+
+	*a = Authorization{
+		Status:   raw.Status,
+		Number:   raw.Number,
+		Env:      raw.Env,
+		Voucher:  raw.Voucher.Value,
+		Messages: raw.Messages,
+	}
+
+	value := strings.TrimSpace(raw.Date)
+	if value == "" {
+		return nil
+	}
+
+	for _, format := range authorizationDateFormats {
+		if date, err := time.Parse(format, value); err == nil {
+			a.Date = date
+			return nil
+		}
+	}
+
+	// Una fecha no reconocida no impide entregar la autorización.
+	a.RawDate = value
+
+	return nil
+}
+
+// MarshalXML serializa la autorización con el formato <autorizacion> que se entrega
+// al comprador, con el comprobante dentro de una sección CDATA.
+func (a Authorization) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	raw := authorizationXML{
+		Status:   a.Status,
+		Number:   a.Number,
+		Env:      a.Env,
+		Voucher:  cdata{Value: a.Voucher},
+		Messages: a.Messages,
+	}
+
+	if !a.Date.IsZero() {
+		raw.Date = a.Date.Format(time.RFC3339)
+	} else {
+		raw.Date = a.RawDate
+	}
+
+	return e.Encode(raw)
+}
+
+// WriteXML escribe el archivo XML de autorización (<autorizacion>) que se entrega al
+// comprador junto con el RIDE.
+func (a *Authorization) WriteXML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+
+	return encoder.Encode(a)
+}
+
+// AuthorizationResponse es la respuesta del servicio AutorizacionComprobantesOffline.
+type AuthorizationResponse struct {
+	// AccessKey: Clave de acceso consultada.
+	AccessKey string `xml:"claveAccesoConsultada"`
+
+	// Count: Número de comprobantes (intentos de autorización) encontrados.
+	Count int `xml:"numeroComprobantes"`
+
+	// Authorizations: Intentos de autorización registrados para la clave de acceso.
+	Authorizations []*Authorization `xml:"autorizaciones>autorizacion"`
+}
+
+// Authorized devuelve la autorización con estado AUTORIZADO, o nil si no existe.
+//
+// El SRI puede devolver varios intentos para la misma clave de acceso, por ejemplo
+// uno NO AUTORIZADO seguido de uno AUTORIZADO.
+func (r *AuthorizationResponse) Authorized() *Authorization {
+	for _, authorization := range r.Authorizations {
+		if authorization.Status == AuthorizationAuthorized {
+			return authorization
+		}
+	}
+
+	return nil
+}
+
+// Latest devuelve el intento de autorización más reciente, o nil si no hay ninguno.
+func (r *AuthorizationResponse) Latest() *Authorization {
+	var latest *Authorization
+	for _, authorization := range r.Authorizations {
+		if latest == nil || authorization.Date.After(latest.Date) {
+			latest = authorization
+		}
+	}
+
+	return latest
+}
+
+// authorizationResult es el contenido del cuerpo SOAP de autorizacionComprobanteResponse.
+type authorizationResult struct {
+	Response AuthorizationResponse `xml:"RespuestaAutorizacionComprobante"`
+}
+
+// AuthorizeVoucher consulta el servicio de autorización del SRI
+// (AutorizacionComprobantesOffline, operación autorizacionComprobante) para la clave
+// de acceso de 49 dígitos indicada, en el ambiente registrado en la propia clave.
+//
+// El formato y el dígito verificador de la clave se validan con sri.CheckAccessKey; si
+// no son correctos se devuelve el error sin llamar al SRI.
+func (s *SRIOnline) AuthorizeVoucher(accessKey string) (*AuthorizationResponse, error) {
+	return s.AuthorizeVoucherContext(context.Background(), accessKey)
+}
+
+// AuthorizeVoucherContext es como AuthorizeVoucher, pero respeta la cancelación y el
+// plazo del contexto.
+func (s *SRIOnline) AuthorizeVoucherContext(ctx context.Context, accessKey string) (*AuthorizationResponse, error) {
+	if err := sri.CheckAccessKey(accessKey); err != nil {
+		return nil, err
+	}
+
+	var key sri.AccessKey
+	_ = key.FromString(accessKey)
+
+	return s.authorize(ctx, key.Env, accessKey)
+}
+
+// authorize invoca la operación autorizacionComprobante en el ambiente indicado.
+func (s *SRIOnline) authorize(ctx context.Context, env sri.EnvType, accessKey string) (*AuthorizationResponse, error) {
+	url, err := s.voucherURL(env, "/AutorizacionComprobantesOffline")
+	if err != nil {
+		return nil, err
+	}
+
+	body := soapRequest(authorizationNamespace, "autorizacionComprobante", "claveAccesoComprobante", accessKey)
+
+	result, err := post[authorizationResult](ctx, s, "AuthorizeVoucher", url, body, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, authorization := range result.Response.Authorizations {
+		if authorization.RawDate != "" {
+			s.reportDrift(SchemaDriftEvent{Op: "AuthorizeVoucher", URL: url, Invalid: []string{"autorizaciones.autorizacion[].fechaAutorizacion"}})
+			break
+		}
+	}
+
+	return &result.Response, nil
+}
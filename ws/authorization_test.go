@@ -0,0 +1,165 @@
+package ws
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAccessKey es una clave de acceso válida del ambiente de pruebas.
+var testAccessKey = sri.AccessKey{
+	Date:          time.Date(2020, time.February, 20, 0, 0, 0, 0, time.UTC),
+	VoucherType:   sri.Invoice,
+	RUC:           "0690000512001",
+	Env:           sri.EnvTest,
+	Establishment: "001",
+	EmissionPoint: "001",
+	Sequential:    "000000001",
+	Code:          "12345678",
+}
+
+const authorizationResponse = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+<soap:Body>
+<ns2:autorizacionComprobanteResponse xmlns:ns2="http://ec.gob.sri.ws.autorizacion">
+<RespuestaAutorizacionComprobante>
+<claveAccesoConsultada>%[1]s</claveAccesoConsultada>
+<numeroComprobantes>2</numeroComprobantes>
+<autorizaciones>
+<autorizacion>
+<estado>NO AUTORIZADO</estado>
+<fechaAutorizacion>2020-02-20T10:00:00-05:00</fechaAutorizacion>
+<ambiente>PRUEBAS</ambiente>
+<comprobante><![CDATA[<factura id="comprobante"></factura>]]></comprobante>
+<mensajes>
+<mensaje>
+<identificador>39</identificador>
+<mensaje>FIRMA INVALIDA</mensaje>
+<tipo>ERROR</tipo>
+</mensaje>
+</mensajes>
+</autorizacion>
+<autorizacion>
+<estado>AUTORIZADO</estado>
+<numeroAutorizacion>%[1]s</numeroAutorizacion>
+<fechaAutorizacion>2020-02-20T10:05:00.123-05:00</fechaAutorizacion>
+<ambiente>PRUEBAS</ambiente>
+<comprobante><![CDATA[<factura id="comprobante"></factura>]]></comprobante>
+<mensajes/>
+</autorizacion>
+</autorizaciones>
+</RespuestaAutorizacionComprobante>
+</ns2:autorizacionComprobanteResponse>
+</soap:Body>
+</soap:Envelope>`
+
+func TestAuthorizeVoucher(t *testing.T) {
+	accessKey, err := testAccessKey.Generate()
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, sriVouchers+"/AutorizacionComprobantesOffline", r.URL.Path)
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Contains(t, string(body), "<claveAccesoComprobante>"+accessKey+"</claveAccesoComprobante>")
+
+		_, _ = w.Write([]byte(strings.ReplaceAll(authorizationResponse, "%[1]s", accessKey)))
+	}))
+	defer server.Close()
+
+	service := NewSRIOnline(WithVouchersURL(sri.EnvTest, server.URL))
+
+	res, err := service.AuthorizeVoucher(accessKey)
+	require.NoError(t, err)
+	assert.Equal(t, accessKey, res.AccessKey)
+	assert.Equal(t, 2, res.Count)
+	require.Len(t, res.Authorizations, 2)
+
+	rejected := res.Authorizations[0]
+	assert.Equal(t, AuthorizationRejected, rejected.Status)
+	assert.Equal(t, "39", rejected.Messages[0].ID)
+
+	authorized := res.Authorized()
+	require.NotNil(t, authorized)
+	assert.Equal(t, accessKey, authorized.Number)
+	assert.Equal(t, `<factura id="comprobante"></factura>`, authorized.Voucher)
+	assert.Equal(t, time.Date(2020, 2, 20, 15, 5, 0, 123000000, time.UTC), authorized.Date.UTC())
+	assert.Same(t, authorized, res.Latest())
+}
+
+func TestAuthorizeVoucher_InvalidAccessKey(t *testing.T) {
+	service := NewSRIOnline(WithVouchersURL(sri.EnvTest, "http://127.0.0.1:0"))
+
+	_, err := service.AuthorizeVoucher("2002202001179125123700120010010058149171234567811")
+	assert.ErrorIs(t, err, sri.ErrAccessKeyCheckDigit)
+
+	_, err = service.AuthorizeVoucher("200220200117912512370012001001005814917123456781")
+	assert.ErrorIs(t, err, sri.ErrInvalidAccessKeyFormat)
+}
+
+func TestAuthorizeVoucher_InvalidDate(t *testing.T) {
+	accessKey, err := testAccessKey.Generate()
+	require.NoError(t, err)
+
+	response := strings.ReplaceAll(authorizationResponse, "%[1]s", accessKey)
+	response = strings.Replace(response, "2020-02-20T10:05:00.123-05:00", "20 de febrero de 2020", 1)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	var events []SchemaDriftEvent
+	service := NewSRIOnline(WithVouchersURL(sri.EnvTest, server.URL), WithHooks(Hooks{OnSchemaDrift: func(event SchemaDriftEvent) {
+		events = append(events, event)
+	}}))
+
+	// The authorization is kept with the raw date instead of failing the call.
+	res, err := service.AuthorizeVoucher(accessKey)
+	require.NoError(t, err)
+
+	authorized := res.Authorized()
+	require.NotNil(t, authorized)
+	assert.True(t, authorized.Date.IsZero())
+	assert.Equal(t, "20 de febrero de 2020", authorized.RawDate)
+	assert.Equal(t, `<factura id="comprobante"></factura>`, authorized.Voucher)
+
+	var buf bytes.Buffer
+	require.NoError(t, authorized.WriteXML(&buf))
+	assert.Contains(t, buf.String(), "<fechaAutorizacion>20 de febrero de 2020</fechaAutorizacion>")
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "AuthorizeVoucher", events[0].Op)
+	assert.Equal(t, []string{"autorizaciones.autorizacion[].fechaAutorizacion"}, events[0].Invalid)
+}
+
+func TestAuthorization_WriteXML(t *testing.T) {
+	authorization := &Authorization{
+		Status:  AuthorizationAuthorized,
+		Number:  "2002202001069000051200110010010000000011234567811",
+		Date:    time.Date(2020, 2, 20, 10, 5, 0, 0, time.FixedZone("ECT", -5*3600)),
+		Env:     "PRUEBAS",
+		Voucher: `<?xml version="1.0" encoding="UTF-8"?><factura id="comprobante"></factura>`,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, authorization.WriteXML(&buf))
+
+	output := buf.String()
+	assert.True(t, strings.HasPrefix(output, xml.Header+"<autorizacion>"))
+	assert.Contains(t, output, "<fechaAutorizacion>2020-02-20T10:05:00-05:00</fechaAutorizacion>")
+	assert.Contains(t, output, `<comprobante><![CDATA[<?xml version="1.0" encoding="UTF-8"?><factura id="comprobante"></factura>]]></comprobante>`)
+
+	var parsed Authorization
+	require.NoError(t, xml.Unmarshal(buf.Bytes(), &parsed))
+	assert.Equal(t, authorization.Voucher, parsed.Voucher)
+	assert.True(t, authorization.Date.Equal(parsed.Date))
+}
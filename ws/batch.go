@@ -0,0 +1,226 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/pinzlab/sricore/id"
+)
+
+const (
+	// DefaultBatchChunkSize es el número de RUC consultados en cada solicitud por lotes.
+	DefaultBatchChunkSize = 20
+
+	// DefaultBatchWorkers es el número de solicitudes por lotes simultáneas.
+	DefaultBatchWorkers = 4
+)
+
+// BatchOptions configura una consulta de contribuyentes por lotes.
+type BatchOptions struct {
+	// ChunkSize: Número de RUC por solicitud a obtenerPorNumerosRuc (por defecto 20).
+	ChunkSize int
+
+	// Workers: Número máximo de solicitudes simultáneas (por defecto 4).
+	Workers int
+
+	// Establishments: Indica si se consultan también los establecimientos de cada RUC.
+	Establishments bool
+}
+
+// BatchResult es el resultado de la consulta de un RUC dentro de un lote.
+type BatchResult struct {
+	// RUC: Número de RUC consultado.
+	RUC string
+
+	// Contributor: Información del contribuyente, o nil si no se encontró.
+	Contributor *Contributor
+
+	// Establishments: Establecimientos del contribuyente, si se solicitaron.
+	Establishments []*Establishment
+
+	// Err: Error de validación o de consulta del RUC, o nil si la consulta fue exitosa.
+	Err error
+}
+
+// GetContributorsBatch consulta varios RUC en lotes con un número limitado de
+// solicitudes simultáneas.
+//
+// Los RUC se validan antes con id.IsRUC; los inválidos no se consultan y su resultado
+// contiene el error de validación. Con la caché activa (WithCache), cada RUC se busca
+// primero en ella, con la misma clave que GetContributors, y solo los que no están se
+// consultan al SRI en solicitudes de hasta ChunkSize RUC separados por comas; la
+// respuesta de cada RUC se almacena por separado. Los establecimientos se consultan con
+// GetEstablishmentsContext. Los RUC que el SRI no devuelve tienen el error
+// ErrContributorNotFound. Los resultados se devuelven en el mismo orden de rucs.
+func (s *SRIOnline) GetContributorsBatch(ctx context.Context, rucs []string, opts BatchOptions) []*BatchResult {
+	if opts.ChunkSize <= 0 {
+		opts.ChunkSize = DefaultBatchChunkSize
+	}
+
+	if opts.Workers <= 0 {
+		opts.Workers = DefaultBatchWorkers
+	}
+
+	results := make([]*BatchResult, len(rucs))
+	unique := map[string]*BatchResult{}
+
+	var pending, misses []*BatchResult
+	for i, ruc := range rucs {
+		ruc = strings.TrimSpace(ruc)
+		if result, ok := unique[ruc]; ok {
+			results[i] = result
+			continue
+		}
+
+		result := &BatchResult{RUC: ruc}
+		if err := id.IsRUC(ruc); err != nil {
+			result.Err = err
+		} else {
+			pending = append(pending, result)
+			if !s.batchFromCache(result) {
+				misses = append(misses, result)
+			}
+		}
+
+		unique[ruc] = result
+		results[i] = result
+	}
+
+	var chunks [][]*BatchResult
+	for start := 0; start < len(misses); start += opts.ChunkSize {
+		chunks = append(chunks, misses[start:min(start+opts.ChunkSize, len(misses))])
+	}
+
+	inParallel(ctx, opts.Workers, chunks, func(chunk []*BatchResult) {
+		s.lookupChunk(ctx, chunk)
+	})
+
+	if opts.Establishments {
+		var found []*BatchResult
+		for _, result := range pending {
+			if result.Contributor != nil {
+				found = append(found, result)
+			}
+		}
+
+		inParallel(ctx, opts.Workers, found, func(result *BatchResult) {
+			result.Establishments, result.Err = s.GetEstablishmentsContext(ctx, result.RUC)
+		})
+
+		// Establecimientos que no se consultaron porque se canceló el contexto.
+		for _, result := range found {
+			if result.Establishments == nil && result.Err == nil {
+				result.Err = ctx.Err()
+			}
+		}
+	}
+
+	// RUC que no se consultaron porque se canceló el contexto.
+	for _, result := range pending {
+		if result.Contributor == nil && result.Err == nil {
+			result.Err = ctx.Err()
+		}
+	}
+
+	return results
+}
+
+// batchFromCache completa el resultado de un RUC con la respuesta almacenada en la
+// caché, si existe.
+func (s *SRIOnline) batchFromCache(result *BatchResult) bool {
+	if s.cache == nil {
+		return false
+	}
+
+	contributors, _, ok := fromCache[[]*Contributor](s, "GetContributors", s.contributorsURL(result.RUC))
+	if !ok {
+		return false
+	}
+
+	result.Contributor = findContributor(contributors, result.RUC)
+	if result.Contributor == nil {
+		result.Err = ErrContributorNotFound
+	}
+
+	return true
+}
+
+// lookupChunk consulta un lote de RUC en una sola solicitud y completa sus resultados.
+// Cada RUC pertenece a un solo lote, por lo que sus resultados no se comparten entre
+// goroutines.
+func (s *SRIOnline) lookupChunk(ctx context.Context, chunk []*BatchResult) {
+	rucs := make([]string, len(chunk))
+	for i, result := range chunk {
+		rucs[i] = result.RUC
+	}
+
+	contributors, err := get[[]*Contributor](ctx, s, "GetContributorsBatch", s.contributorsURL(rucs...))
+	if err != nil {
+		for _, result := range chunk {
+			result.Err = err
+		}
+		return
+	}
+
+	for _, result := range chunk {
+		result.Contributor = findContributor(contributors, result.RUC)
+		if result.Contributor == nil {
+			result.Err = ErrContributorNotFound
+		}
+
+		if s.cache == nil {
+			continue
+		}
+
+		// Cada RUC se almacena con la respuesta que devolvería GetContributors.
+		found := []*Contributor{}
+		if result.Contributor != nil {
+			found = append(found, result.Contributor)
+		}
+
+		if body, err := json.Marshal(found); err == nil {
+			_, _ = s.toCache(s.contributorsURL(result.RUC), body, len(found) == 0)
+		}
+	}
+}
+
+// findContributor devuelve el contribuyente con el RUC indicado, o nil si no está.
+func findContributor(contributors []*Contributor, ruc string) *Contributor {
+	for _, contributor := range contributors {
+		if contributor != nil && contributor.Ruc == ruc {
+			return contributor
+		}
+	}
+
+	return nil
+}
+
+// inParallel llama a fn con cada elemento usando hasta workers goroutines. Los elementos
+// pendientes no se procesan si se cancela el contexto.
+func inParallel[T any](ctx context.Context, workers int, items []T, fn func(T)) {
+	queue := make(chan T)
+	go func() {
+		defer close(queue)
+		for _, item := range items {
+			select {
+			case queue <- item:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range min(workers, max(len(items), 1)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range queue {
+				fn(item)
+			}
+		}()
+	}
+	wg.Wait()
+}
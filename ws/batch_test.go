@@ -0,0 +1,157 @@
+package ws_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/id"
+	"github.com/pinzlab/sricore/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetContributorsBatch(t *testing.T) {
+	service, server := newTestService(t)
+	server.AddContributor(&ws.Contributor{Ruc: "1790016919001", BusinessName: "CORPORACION FAVORITA C.A."})
+	server.AddContributor(&ws.Contributor{Ruc: "1768152560001", BusinessName: "EMPRESA PUBLICA"})
+
+	rucs := []string{eersaRuc, "1790016919001", "1234567890001", "1768152560001", "0601234560001", eersaRuc}
+
+	results := service.GetContributorsBatch(context.Background(), rucs, ws.BatchOptions{
+		Workers:        2,
+		Establishments: true,
+	})
+	require.Len(t, results, len(rucs))
+
+	for i, result := range results {
+		assert.Equal(t, rucs[i], result.RUC)
+	}
+
+	assert.NoError(t, results[0].Err)
+	assert.Equal(t, "EMPRESA ELECTRICA RIOBAMBA SA", results[0].Contributor.BusinessName)
+	assert.Len(t, results[0].Establishments, 1)
+	assert.Same(t, results[0], results[5])
+
+	assert.NoError(t, results[1].Err)
+	assert.Empty(t, results[1].Establishments)
+
+	assert.Equal(t, id.IsRUC("1234567890001"), results[2].Err)
+	assert.Nil(t, results[2].Contributor)
+
+	assert.Equal(t, "EMPRESA PUBLICA", results[3].Contributor.BusinessName)
+	assert.ErrorIs(t, results[4].Err, ws.ErrContributorNotFound)
+
+	// Una solicitud para los RUC válidos distintos y una por cada contribuyente encontrado.
+	assert.Equal(t, 4, server.Requests())
+}
+
+func TestGetContributorsBatch_Outage(t *testing.T) {
+	service, server := newTestService(t)
+	server.Outage(-1)
+
+	results := service.GetContributorsBatch(context.Background(), []string{eersaRuc, "1790016919001"}, ws.BatchOptions{})
+	for _, result := range results {
+		assert.ErrorIs(t, result.Err, ws.ErrHTTPStatus)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results = service.GetContributorsBatch(ctx, []string{eersaRuc}, ws.BatchOptions{})
+	assert.ErrorIs(t, results[0].Err, context.Canceled)
+}
+
+// newFixtureServer responde las consultas del catastro con los archivos de
+// testdata/catastro y registra las consultas recibidas.
+func newFixtureServer(t *testing.T) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var queries []string
+
+	files := map[string]string{
+		"obtenerPorNumerosRuc":  "contribuyente",
+		"consultarPorNumeroRuc": "establecimientos",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, path.Base(r.URL.Path)+"?"+r.URL.RawQuery)
+		mu.Unlock()
+
+		// obtenerPorNumerosRuc acepta varios RUC separados por comas.
+		items := []json.RawMessage{}
+		for _, ruc := range strings.Split(r.URL.Query().Get("ruc")+r.URL.Query().Get("numeroRuc"), ",") {
+			var fixture []json.RawMessage
+			if data, err := os.ReadFile("testdata/catastro/" + files[path.Base(r.URL.Path)] + "-" + ruc + ".json"); err == nil {
+				require.NoError(t, json.Unmarshal(data, &fixture))
+			}
+			items = append(items, fixture...)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(items)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), queries...)
+	}
+}
+
+func TestGetContributorsBatch_Fixture(t *testing.T) {
+	server, queries := newFixtureServer(t)
+	service := ws.NewSRIOnline(ws.WithBaseURL(server.URL), ws.WithCache(ws.NewMemoryCache(), time.Hour, time.Minute))
+
+	// EERSA ya está en la caché y no se vuelve a consultar en el lote.
+	_, err := service.GetContributors(eersaRuc)
+	require.NoError(t, err)
+
+	rucs := []string{eersaRuc, "1790016919001", "0601234560001", "1768152560001"}
+	opts := ws.BatchOptions{ChunkSize: 2, Workers: 3, Establishments: true}
+
+	results := service.GetContributorsBatch(context.Background(), rucs, opts)
+	require.Len(t, results, 4)
+
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, "EMPRESA ELECTRICA RIOBAMBA SA", results[0].Contributor.BusinessName)
+	require.Len(t, results[0].Establishments, 2)
+	assert.False(t, results[0].Establishments[1].IsOpen())
+
+	require.NoError(t, results[1].Err)
+	assert.Equal(t, "CORPORACION FAVORITA C.A.", results[1].Contributor.BusinessName)
+	assert.Len(t, results[1].Establishments, 1)
+
+	assert.ErrorIs(t, results[2].Err, ws.ErrContributorNotFound)
+	assert.ErrorIs(t, results[3].Err, ws.ErrContributorNotFound)
+
+	// Solo los RUC que no están en la caché se consultan, en lotes de ChunkSize.
+	assert.ElementsMatch(t, []string{
+		"obtenerPorNumerosRuc?&ruc=0690000512001",
+		"obtenerPorNumerosRuc?&ruc=1790016919001,0601234560001",
+		"obtenerPorNumerosRuc?&ruc=1768152560001",
+		"consultarPorNumeroRuc?numeroRuc=0690000512001",
+		"consultarPorNumeroRuc?numeroRuc=1790016919001",
+	}, queries())
+
+	// Los contribuyentes y establecimientos se obtienen de la caché en la siguiente
+	// consulta, también con GetContributors.
+	results = service.GetContributorsBatch(context.Background(), rucs, opts)
+	assert.Len(t, results[0].Establishments, 2)
+	assert.Equal(t, "CORPORACION FAVORITA C.A.", results[1].Contributor.BusinessName)
+	assert.ErrorIs(t, results[2].Err, ws.ErrContributorNotFound)
+
+	contributors, err := service.GetContributors("1790016919001")
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+	assert.Equal(t, results[1].Contributor.BusinessName, contributors[0].BusinessName)
+	assert.Len(t, queries(), 5)
+}
@@ -0,0 +1,346 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+)
+
+// Service es un servicio del SRI con disponibilidad propia.
+type Service string
+
+const (
+	// ServiceCatastro es la plataforma SRI en línea: catastro de contribuyentes y
+	// matriculación vehicular.
+	ServiceCatastro Service = "catastro"
+
+	// ServiceVouchersTest son los servicios web de comprobantes del ambiente de pruebas.
+	ServiceVouchersTest Service = "comprobantes-pruebas"
+
+	// ServiceVouchersProd son los servicios web de comprobantes del ambiente de producción.
+	ServiceVouchersProd Service = "comprobantes-produccion"
+)
+
+// VouchersService devuelve el servicio de comprobantes del ambiente indicado.
+func VouchersService(env sri.EnvType) Service {
+	if env == sri.EnvProd {
+		return ServiceVouchersProd
+	}
+
+	return ServiceVouchersTest
+}
+
+// HealthStatus es la disponibilidad de un servicio del SRI.
+type HealthStatus int
+
+const (
+	// StatusUp indica que el servicio responde con normalidad.
+	StatusUp HealthStatus = iota
+
+	// StatusDegraded indica que el servicio tuvo fallas recientes, sin llegar a abrir el
+	// circuito, o que se está probando su recuperación.
+	StatusDegraded
+
+	// StatusDown indica que el circuito está abierto: las solicitudes fallan de inmediato
+	// con ErrCircuitOpen.
+	StatusDown
+)
+
+// String devuelve el nombre del estado.
+func (h HealthStatus) String() string {
+	switch h {
+	case StatusUp:
+		return "DISPONIBLE"
+	case StatusDegraded:
+		return "DEGRADADO"
+	case StatusDown:
+		return "NO DISPONIBLE"
+	default:
+		return "DESCONOCIDO"
+	}
+}
+
+// StatusChange describe un cambio en la disponibilidad de un servicio.
+type StatusChange struct {
+	// Service: Servicio que cambió de estado.
+	Service Service
+
+	// From: Estado anterior.
+	From HealthStatus
+
+	// To: Estado nuevo.
+	To HealthStatus
+
+	// Err: Error que provocó el cambio (si aplica).
+	Err error
+
+	// At: Fecha del cambio.
+	At time.Time
+}
+
+// circuitState es el estado interno del circuito de un servicio.
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuit es el circuito de un servicio.
+type circuit struct {
+	state    circuitState
+	failures int
+	openedAt time.Time
+
+	// trial indica que hay una solicitud de prueba en curso con el circuito semiabierto.
+	trial bool
+}
+
+// status devuelve la disponibilidad que representa el circuito.
+func (c *circuit) status() HealthStatus {
+	switch {
+	case c.state == circuitOpen:
+		return StatusDown
+	case c.state == circuitHalfOpen || c.failures > 0:
+		return StatusDegraded
+	default:
+		return StatusUp
+	}
+}
+
+// CircuitBreaker corta las solicitudes a un servicio del SRI que no está disponible, para
+// que fallen de inmediato con ErrCircuitOpen en lugar de esperar el tiempo máximo. Cada
+// servicio tiene su propio circuito. Una misma instancia puede compartirse entre varios
+// clientes y goroutines.
+//
+// Tras threshold fallas consecutivas (errores de conexión, tiempos agotados o estados
+// 500, 502, 503 y 504, incluidos los SOAP Fault con estado 500) el circuito se abre. Pasado el tiempo de espera se permite una
+// solicitud de prueba: si tiene éxito el circuito se cierra y si falla se vuelve a abrir.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	circuits  map[Service]*circuit
+	listeners []func(StatusChange)
+}
+
+// NewCircuitBreaker crea un circuito que se abre tras threshold fallas consecutivas y
+// permite una solicitud de prueba cada cooldown.
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		circuits:  map[Service]*circuit{},
+	}
+}
+
+// OnChange registra una función que se invoca cada vez que cambia la disponibilidad de un
+// servicio. La función no debe usar el circuito de forma bloqueante.
+func (b *CircuitBreaker) OnChange(fn func(StatusChange)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.listeners = append(b.listeners, fn)
+}
+
+// Status devuelve la disponibilidad de un servicio.
+func (b *CircuitBreaker) Status(service Service) HealthStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.circuit(service).status()
+}
+
+// Statuses devuelve la disponibilidad de los servicios usados hasta el momento.
+func (b *CircuitBreaker) Statuses() map[Service]HealthStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make(map[Service]HealthStatus, len(b.circuits))
+	for service, c := range b.circuits {
+		statuses[service] = c.status()
+	}
+
+	return statuses
+}
+
+// Available indica si una solicitud al servicio se enviaría: el circuito no está abierto
+// o ya puede probarse su recuperación. Permite decidir de antemano si emitir en modo
+// diferido o encolar el comprobante.
+func (b *CircuitBreaker) Available(service Service) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(service)
+	switch c.state {
+	case circuitOpen:
+		return time.Since(c.openedAt) >= b.cooldown
+	case circuitHalfOpen:
+		return !c.trial
+	default:
+		return true
+	}
+}
+
+// Reset cierra el circuito de todos los servicios.
+func (b *CircuitBreaker) Reset() {
+	b.mu.Lock()
+	changes := make([]StatusChange, 0, len(b.circuits))
+	for service, c := range b.circuits {
+		if from := c.status(); from != StatusUp {
+			changes = append(changes, StatusChange{Service: service, From: from, To: StatusUp, At: time.Now()})
+		}
+	}
+	b.circuits = map[Service]*circuit{}
+	listeners := b.listeners
+	b.mu.Unlock()
+
+	for _, change := range changes {
+		for _, fn := range listeners {
+			fn(change)
+		}
+	}
+}
+
+// allow indica si puede enviarse una solicitud al servicio y si esta es la solicitud de
+// prueba del circuito semiabierto, del que solo se permite una a la vez.
+func (b *CircuitBreaker) allow(service Service) (allowed, trial bool) {
+	b.mu.Lock()
+	c := b.circuit(service)
+	from := c.status()
+
+	allowed = true
+	switch c.state {
+	case circuitOpen:
+		allowed = time.Since(c.openedAt) >= b.cooldown
+		if allowed {
+			c.state = circuitHalfOpen
+			c.trial, trial = true, true
+		}
+	case circuitHalfOpen:
+		allowed = !c.trial
+		if allowed {
+			c.trial, trial = true, true
+		}
+	}
+
+	change := b.change(service, from, c, nil)
+	b.mu.Unlock()
+
+	b.notify(change)
+
+	return allowed, trial
+}
+
+// record registra el resultado de una solicitud al servicio. Las solicitudes canceladas
+// por quien llama no cuentan como éxito ni como falla. trial indica que la solicitud es
+// la de prueba concedida por allow; solo entonces se permite una nueva prueba.
+func (b *CircuitBreaker) record(ctx context.Context, service Service, trial bool, status int, err error) {
+	b.mu.Lock()
+	c := b.circuit(service)
+	from := c.status()
+	if trial {
+		c.trial = false
+	}
+
+	switch {
+	case ctx.Err() != nil:
+	case outage(status, err):
+		if err == nil {
+			err = fmt.Errorf("%w (HTTP %d)", ErrHTTPStatus, status)
+		}
+
+		c.failures++
+		if c.state == circuitHalfOpen || c.failures >= b.threshold {
+			c.state = circuitOpen
+			c.openedAt = time.Now()
+		}
+	default:
+		c.state = circuitClosed
+		c.failures = 0
+	}
+
+	change := b.change(service, from, c, err)
+	b.mu.Unlock()
+
+	b.notify(change)
+}
+
+// circuit devuelve el circuito de un servicio, creándolo si no existe.
+func (b *CircuitBreaker) circuit(service Service) *circuit {
+	c, ok := b.circuits[service]
+	if !ok {
+		c = &circuit{}
+		b.circuits[service] = c
+	}
+
+	return c
+}
+
+// change devuelve el cambio de estado del circuito, o nil si no cambió.
+func (b *CircuitBreaker) change(service Service, from HealthStatus, c *circuit, err error) *StatusChange {
+	to := c.status()
+	if to == from {
+		return nil
+	}
+
+	return &StatusChange{Service: service, From: from, To: to, Err: err, At: time.Now()}
+}
+
+// notify invoca las funciones registradas con OnChange.
+func (b *CircuitBreaker) notify(change *StatusChange) {
+	if change == nil {
+		return
+	}
+
+	b.mu.Lock()
+	listeners := b.listeners
+	b.mu.Unlock()
+
+	for _, fn := range listeners {
+		fn(*change)
+	}
+}
+
+// outage indica si el resultado de una solicitud muestra que el servicio no está
+// disponible. Un estado 500 cuenta como falla aunque la respuesta sea un SOAP Fault; los
+// demás errores (por ejemplo, un SOAP Fault con otro estado) demuestran que responde.
+func outage(status int, err error) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+
+	return errors.Is(err, ErrHTTPRequest) || errors.Is(err, ErrReadBody)
+}
+
+// WithCircuitBreaker corta las solicitudes a los servicios del SRI que no están
+// disponibles, para que fallen de inmediato con ErrCircuitOpen. El mismo circuito puede
+// compartirse entre varios clientes y con un HealthMonitor.
+func WithCircuitBreaker(breaker *CircuitBreaker) Option {
+	return func(s *SRIOnline) {
+		s.breaker = breaker
+	}
+}
+
+// service devuelve el servicio del SRI al que corresponde una URL.
+func (s *SRIOnline) service(url string) Service {
+	for env, base := range s.vouchersURL {
+		if strings.HasPrefix(url, base+sriVouchers) {
+			return VouchersService(env)
+		}
+	}
+
+	return ServiceCatastro
+}
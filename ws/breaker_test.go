@@ -0,0 +1,219 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_Opens(t *testing.T) {
+	server, requests := newFlakyServer(10, `true`)
+	defer server.Close()
+
+	var changes []StatusChange
+	breaker := NewCircuitBreaker(2, time.Hour)
+	breaker.OnChange(func(change StatusChange) { changes = append(changes, change) })
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCircuitBreaker(breaker))
+
+	for range 2 {
+		_, err := service.CheckRUC("0690000512001")
+		require.ErrorIs(t, err, ErrHTTPStatus)
+	}
+	assert.Equal(t, StatusDown, breaker.Status(ServiceCatastro))
+	assert.False(t, breaker.Available(ServiceCatastro))
+
+	// The open circuit fails fast without reaching the SRI.
+	_, err := service.GetContributors("0690000512001")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), requests.Load())
+
+	require.Len(t, changes, 2)
+	assert.Equal(t, ServiceCatastro, changes[0].Service)
+	assert.Equal(t, StatusUp, changes[0].From)
+	assert.Equal(t, StatusDegraded, changes[0].To)
+	assert.Equal(t, StatusDegraded, changes[1].From)
+	assert.Equal(t, StatusDown, changes[1].To)
+	assert.ErrorIs(t, changes[1].Err, ErrHTTPStatus)
+
+	// Other services keep their own circuit.
+	assert.Equal(t, StatusUp, breaker.Status(ServiceVouchersProd))
+	assert.Equal(t, map[Service]HealthStatus{ServiceCatastro: StatusDown, ServiceVouchersProd: StatusUp}, breaker.Statuses())
+
+	// Internal server errors, with or without a SOAP fault, count as failures too.
+	for _, body := range []string{"", `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body><soap:Fault><faultcode>soap:Server</faultcode><faultstring>Error interno</faultstring></soap:Fault></soap:Body></soap:Envelope>`} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/xml")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(body))
+		}))
+		defer server.Close()
+
+		breaker := NewCircuitBreaker(2, time.Hour)
+		service := NewSRIOnline(WithVouchersURL(sri.EnvTest, server.URL), WithCircuitBreaker(breaker))
+
+		for range 2 {
+			_, err := service.ValidateVoucherContext(context.Background(), sri.EnvTest, []byte("<factura/>"))
+			if body == "" {
+				require.ErrorIs(t, err, ErrHTTPStatus)
+			} else {
+				require.ErrorIs(t, err, ErrSOAPFault)
+			}
+		}
+		assert.Equal(t, StatusDown, breaker.Status(ServiceVouchersTest), body)
+	}
+}
+
+func TestCircuitBreaker_Recovers(t *testing.T) {
+	server, requests := newFlakyServer(2, `true`)
+	defer server.Close()
+
+	breaker := NewCircuitBreaker(1, 10*time.Millisecond)
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCircuitBreaker(breaker))
+
+	_, err := service.CheckRUC("0690000512001")
+	require.ErrorIs(t, err, ErrHTTPStatus)
+	assert.Equal(t, StatusDown, breaker.Status(ServiceCatastro))
+
+	// A failed trial opens the circuit again.
+	time.Sleep(20 * time.Millisecond)
+	assert.True(t, breaker.Available(ServiceCatastro))
+	_, err = service.CheckRUC("0690000512001")
+	require.ErrorIs(t, err, ErrHTTPStatus)
+	assert.Equal(t, StatusDown, breaker.Status(ServiceCatastro))
+
+	_, err = service.CheckRUC("0690000512001")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+
+	// A successful trial closes it.
+	time.Sleep(20 * time.Millisecond)
+	exists, err := service.CheckRUC("0690000512001")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, StatusUp, breaker.Status(ServiceCatastro))
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestCircuitBreaker_StopsRetries(t *testing.T) {
+	server, requests := newFlakyServer(10, `true`)
+	defer server.Close()
+
+	breaker := NewCircuitBreaker(2, time.Hour)
+	service := NewSRIOnline(WithBaseURL(server.URL), WithRetry(RetryPolicy{Attempts: 5, Initial: time.Millisecond}), WithCircuitBreaker(breaker))
+
+	_, err := service.CheckRUC("0690000512001")
+	require.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestCircuitBreaker_HalfOpen(t *testing.T) {
+	breaker := NewCircuitBreaker(1, 0)
+	ctx := context.Background()
+
+	breaker.record(ctx, ServiceVouchersTest, false, http.StatusServiceUnavailable, nil)
+	assert.Equal(t, StatusDown, breaker.Status(ServiceVouchersTest))
+
+	// Only one trial request at a time.
+	allowed, trial := breaker.allow(ServiceVouchersTest)
+	assert.True(t, allowed)
+	assert.True(t, trial)
+	assert.Equal(t, StatusDegraded, breaker.Status(ServiceVouchersTest))
+	allowed, trial = breaker.allow(ServiceVouchersTest)
+	assert.False(t, allowed)
+	assert.False(t, trial)
+	assert.False(t, breaker.Available(ServiceVouchersTest))
+
+	// A request canceled by the caller neither closes nor opens the circuit.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	breaker.record(canceled, ServiceVouchersTest, true, 0, newError("op", "url", 0, nil, ErrHTTPRequest, context.Canceled))
+	assert.Equal(t, StatusDegraded, breaker.Status(ServiceVouchersTest))
+	allowed, trial = breaker.allow(ServiceVouchersTest)
+	assert.True(t, allowed)
+	assert.True(t, trial)
+
+	// A SOAP fault with status 500 is a failure and opens the circuit again.
+	breaker.record(ctx, ServiceVouchersTest, true, http.StatusInternalServerError, newError("op", "url", 500, nil, ErrSOAPFault, nil))
+	assert.Equal(t, StatusDown, breaker.Status(ServiceVouchersTest))
+
+	// Any other SOAP fault shows the service answers.
+	_, trial = breaker.allow(ServiceVouchersTest)
+	require.True(t, trial)
+	breaker.record(ctx, ServiceVouchersTest, true, http.StatusOK, newError("op", "url", 200, nil, ErrSOAPFault, nil))
+	assert.Equal(t, StatusUp, breaker.Status(ServiceVouchersTest))
+}
+
+func TestCircuitBreaker_TrialOwner(t *testing.T) {
+	breaker := NewCircuitBreaker(1, 0)
+	ctx := context.Background()
+
+	// A request sent while the circuit was closed finishes after the circuit opens.
+	allowed, trial := breaker.allow(ServiceCatastro)
+	require.True(t, allowed)
+	assert.False(t, trial)
+
+	breaker.record(ctx, ServiceCatastro, false, http.StatusServiceUnavailable, nil)
+	allowed, trial = breaker.allow(ServiceCatastro)
+	require.True(t, allowed)
+	require.True(t, trial)
+
+	// The stale request does not release the trial in progress.
+	breaker.record(canceledContext(), ServiceCatastro, false, 0, newError("op", "url", 0, nil, ErrHTTPRequest, context.Canceled))
+	allowed, _ = breaker.allow(ServiceCatastro)
+	assert.False(t, allowed)
+	assert.False(t, breaker.Available(ServiceCatastro))
+
+	// The trial request releases it.
+	breaker.record(ctx, ServiceCatastro, true, http.StatusOK, nil)
+	assert.Equal(t, StatusUp, breaker.Status(ServiceCatastro))
+	assert.True(t, breaker.Available(ServiceCatastro))
+}
+
+// canceledContext devuelve un contexto ya cancelado.
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	return ctx
+}
+
+func TestCircuitBreaker_Reset(t *testing.T) {
+	breaker := NewCircuitBreaker(1, time.Hour)
+
+	var changes []StatusChange
+	breaker.OnChange(func(change StatusChange) { changes = append(changes, change) })
+
+	breaker.record(context.Background(), ServiceCatastro, false, 0, newError("op", "url", 0, nil, ErrHTTPRequest, nil))
+	breaker.Reset()
+
+	assert.Equal(t, StatusUp, breaker.Status(ServiceCatastro))
+	require.Len(t, changes, 2)
+	assert.Equal(t, StatusDown, changes[1].From)
+	assert.Equal(t, StatusUp, changes[1].To)
+}
+
+func TestSRIOnline_Service(t *testing.T) {
+	service := NewSRIOnline()
+
+	url, err := service.voucherURL(sri.EnvProd, "/RecepcionComprobantesOffline")
+	require.NoError(t, err)
+	assert.Equal(t, ServiceVouchersProd, service.service(url))
+
+	url, err = service.voucherURL(sri.EnvTest, "/AutorizacionComprobantesOffline")
+	require.NoError(t, err)
+	assert.Equal(t, ServiceVouchersTest, service.service(url))
+
+	assert.Equal(t, ServiceCatastro, service.service(service.vehicleURL("/BaseVehiculo")))
+}
+
+func TestHealthStatus_String(t *testing.T) {
+	assert.Equal(t, "DISPONIBLE", StatusUp.String())
+	assert.Equal(t, "DEGRADADO", StatusDegraded.String())
+	assert.Equal(t, "NO DISPONIBLE", StatusDown.String())
+}
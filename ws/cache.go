@@ -0,0 +1,273 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Cache almacena las respuestas de las consultas del catastro (CheckRUC,
+// GetContributors y GetEstablishments). Los valores son documentos JSON con el cuerpo de
+// la respuesta del SRI y la fecha en que se obtuvo, de modo que pueden guardarse en un
+// almacenamiento externo.
+//
+// Las implementaciones deben poder usarse desde varias goroutines a la vez.
+type Cache interface {
+	// Get devuelve el valor almacenado para la clave, si existe y no expiró.
+	Get(key string) ([]byte, bool)
+
+	// Set almacena el valor para la clave durante el tiempo indicado.
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// MemoryCache es una implementación de Cache en memoria.
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	now     func() time.Time
+}
+
+// cacheEntry es un valor almacenado en MemoryCache.
+type cacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// NewMemoryCache crea una caché en memoria vacía.
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: map[string]cacheEntry{}, now: time.Now}
+}
+
+// Get implementa Cache.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return nil, false
+	}
+
+	return entry.value, true
+}
+
+// Set implementa Cache.
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = cacheEntry{value: value, expires: c.now().Add(ttl)}
+}
+
+// Purge elimina los valores expirados.
+func (c *MemoryCache) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+		}
+	}
+}
+
+// Len devuelve el número de valores almacenados, incluidos los expirados aún no purgados.
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// cachedResponse es el valor almacenado en la caché: el cuerpo de la respuesta del SRI y
+// la fecha en que se obtuvo.
+type cachedResponse struct {
+	FetchedAt time.Time       `json:"fetchedAt"`
+	Body      json.RawMessage `json:"body"`
+}
+
+// flight agrupa las solicitudes idénticas en curso para que solo una llegue al SRI.
+type flight struct {
+	mu    sync.Mutex
+	calls map[string]*flightCall
+}
+
+// flightCall es una solicitud en curso y su resultado.
+type flightCall struct {
+	done  chan struct{}
+	value []byte
+	err   error
+}
+
+// errFlightAborted es el resultado de una solicitud compartida que terminó sin devolver
+// un valor, por ejemplo por un pánico.
+var errFlightAborted = errors.New("La solicitud compartida al SRI terminó sin resultado")
+
+// do ejecuta fn una sola vez por clave mientras haya solicitudes en curso. fn se ejecuta
+// en su propia goroutine y cada solicitud, incluida la que la inició, espera el resultado
+// hasta que se cancele su propio contexto.
+//
+// fn recibe un contexto que no se cancela con el de la solicitud que la inició, de modo
+// que cancelar esa solicitud no haga fallar a las que esperan; el tiempo máximo de la
+// solicitud compartida lo impone fn. Un pánico en fn se devuelve como errFlightAborted.
+func (f *flight) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	f.mu.Lock()
+	if f.calls == nil {
+		f.calls = map[string]*flightCall{}
+	}
+
+	call, ok := f.calls[key]
+	if !ok {
+		call = &flightCall{done: make(chan struct{}), err: errFlightAborted}
+		f.calls[key] = call
+		go f.run(context.WithoutCancel(ctx), key, call, fn)
+	}
+	f.mu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// run ejecuta la solicitud compartida y notifica su resultado a las solicitudes que
+// esperan.
+func (f *flight) run(ctx context.Context, key string, call *flightCall, fn func(ctx context.Context) ([]byte, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			call.value, call.err = nil, fmt.Errorf("%w: %v", errFlightAborted, r)
+		}
+
+		f.mu.Lock()
+		delete(f.calls, key)
+		f.mu.Unlock()
+		close(call.done)
+	}()
+
+	call.value, call.err = fn(ctx)
+}
+
+// cached es como get, pero consulta primero la caché configurada y agrupa las
+// solicitudes idénticas en curso. Las respuestas vacías (según empty) se almacenan con
+// el tiempo de vida de la caché negativa.
+func cached[T any](ctx context.Context, s *SRIOnline, op, url string, empty func(T) bool) (T, error) {
+	result, _, err := cachedAt(ctx, s, op, url, empty)
+	return result, err
+}
+
+// cachedAt es como cached, pero también devuelve la fecha en que se obtuvo la respuesta
+// del SRI, que para las respuestas almacenadas es anterior a la consulta.
+func cachedAt[T any](ctx context.Context, s *SRIOnline, op, url string, empty func(T) bool) (T, time.Time, error) {
+	var result T
+
+	if s.cache == nil {
+		result, err := get[T](ctx, s, op, url)
+		return result, time.Now(), err
+	}
+
+	if result, fetchedAt, ok := fromCache[T](s, op, url); ok {
+		return result, fetchedAt, nil
+	}
+
+	value, err := s.flight.do(ctx, url, func(ctx context.Context) ([]byte, error) {
+		ctx, cancel := s.withTimeout(ctx)
+		defer cancel()
+
+		var body []byte
+		var result T
+
+		err := s.observe(ctx, op, http.MethodGet, url, func(ctx context.Context) (err error) {
+			if body, err = s.fetch(ctx, op, url); err != nil {
+				return err
+			}
+
+			if result, err = decode[T](s, op, url, body); err != nil {
+				return err
+			}
+			checkSchema(s, op, url, body, result)
+
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return s.toCache(url, body, empty(result))
+	})
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			err = s.fail(op, url, 0, nil, ErrHTTPRequest, err)
+		}
+		return result, time.Time{}, err
+	}
+
+	var response cachedResponse
+	if err := json.Unmarshal(value, &response); err != nil {
+		return result, time.Time{}, s.fail(op, url, http.StatusOK, value, ErrJSONUnmarshal, err)
+	}
+
+	// Cada llamada deserializa su propia copia, de modo que el resultado puede modificarse.
+	result, err = decode[T](s, op, url, response.Body)
+
+	return result, response.FetchedAt, err
+}
+
+// fromCache devuelve la respuesta almacenada en la caché para la URL, si existe y puede
+// deserializarse, junto con la fecha en que se obtuvo del SRI.
+func fromCache[T any](s *SRIOnline, op, url string) (T, time.Time, bool) {
+	var result T
+
+	value, ok := s.cache.Get(url)
+	if !ok {
+		return result, time.Time{}, false
+	}
+
+	var response cachedResponse
+	if err := json.Unmarshal(value, &response); err != nil {
+		return result, time.Time{}, false
+	}
+
+	result, err := decode[T](s, op, url, response.Body)
+	if err != nil {
+		return result, time.Time{}, false
+	}
+
+	return result, response.FetchedAt, true
+}
+
+// toCache almacena el cuerpo de una respuesta del SRI para la URL, con el tiempo de vida
+// de la caché negativa si la respuesta está vacía, y devuelve el valor almacenado.
+func (s *SRIOnline) toCache(url string, body []byte, empty bool) ([]byte, error) {
+	value, err := json.Marshal(cachedResponse{FetchedAt: time.Now(), Body: body})
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := s.cacheTTL
+	if empty {
+		ttl = s.negativeTTL
+	}
+
+	if ttl > 0 {
+		s.cache.Set(url, value, ttl)
+	}
+
+	return value, nil
+}
+
+// isEmpty indica si una lista de resultados del catastro está vacía.
+func isEmpty[T any](values []T) bool {
+	return len(values) == 0
+}
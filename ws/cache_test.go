@@ -0,0 +1,281 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cache := NewMemoryCache()
+	cache.now = func() time.Time { return now }
+
+	cache.Set("a", []byte("1"), time.Minute)
+	cache.Set("b", []byte("2"), time.Hour)
+
+	value, ok := cache.Get("a")
+	require.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+
+	now = now.Add(time.Minute)
+
+	_, ok = cache.Get("a")
+	assert.False(t, ok)
+
+	now = now.Add(time.Hour)
+	cache.Purge()
+	assert.Zero(t, cache.Len())
+}
+
+// newCatastroServer crea un servidor del catastro que cuenta las solicitudes.
+func newCatastroServer(delay time.Duration) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		time.Sleep(delay)
+
+		switch {
+		case strings.Contains(r.URL.RawQuery, "0690000512001"):
+			_, _ = w.Write([]byte(`[{"numeroRuc":"0690000512001","razonSocial":"EMPRESA ELECTRICA RIOBAMBA SA"}]`))
+		default:
+			_, _ = w.Write([]byte(`[]`))
+		}
+	}))
+
+	return server, &requests
+}
+
+func TestCache_Contributors(t *testing.T) {
+	server, requests := newCatastroServer(0)
+	defer server.Close()
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCache(NewMemoryCache(), time.Hour, time.Minute))
+
+	for range 3 {
+		contributors, err := service.GetContributors("0690000512001")
+		require.NoError(t, err)
+		require.Len(t, contributors, 1)
+
+		// Each call receives its own copy.
+		contributors[0].BusinessName = "MODIFICADO"
+	}
+
+	for range 2 {
+		contributors, err := service.GetContributors("0601234560001")
+		require.NoError(t, err)
+		assert.Empty(t, contributors)
+	}
+
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestCache_NoNegative(t *testing.T) {
+	server, requests := newCatastroServer(0)
+	defer server.Close()
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCache(NewMemoryCache(), time.Hour, 0))
+
+	for range 2 {
+		_, err := service.GetEstablishments("0601234560001")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestCache_Singleflight(t *testing.T) {
+	server, requests := newCatastroServer(50 * time.Millisecond)
+	defer server.Close()
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCache(NewMemoryCache(), time.Hour, time.Minute))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			contributors, err := service.GetContributors("0690000512001")
+			assert.NoError(t, err)
+			assert.Len(t, contributors, 1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+// newBlockingServer crea un servidor del catastro que responde solo después de cerrar
+// release, y notifica en started cada solicitud recibida.
+func newBlockingServer(t *testing.T) (server *httptest.Server, started chan struct{}, release chan struct{}) {
+	started = make(chan struct{}, 10)
+	release = make(chan struct{})
+
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		_, _ = w.Write([]byte(`[{"numeroRuc":"0690000512001","razonSocial":"EMPRESA ELECTRICA RIOBAMBA SA"}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server, started, release
+}
+
+// waitFlight espera a que haya una solicitud en curso para la clave.
+func waitFlight(t *testing.T, f *flight, key string) {
+	t.Helper()
+
+	require.Eventually(t, func() bool {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		return f.calls[key] != nil
+	}, time.Second, time.Millisecond)
+}
+
+func TestCache_SingleflightLeaderCanceled(t *testing.T) {
+	server, started, release := newBlockingServer(t)
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCache(NewMemoryCache(), time.Hour, time.Minute))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	leader := make(chan error, 1)
+	go func() {
+		_, err := service.GetContributorsContext(ctx, "0690000512001")
+		leader <- err
+	}()
+	<-started
+
+	follower := make(chan error, 1)
+	go func() {
+		contributors, err := service.GetContributorsContext(context.Background(), "0690000512001")
+		assert.Len(t, contributors, 1)
+		follower <- err
+	}()
+
+	// Cancelar la solicitud que inició la consulta la termina sin interrumpir la
+	// consulta compartida
+	cancel()
+	select {
+	case err := <-leader:
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, ErrHTTPRequest)
+	case <-time.After(time.Second):
+		t.Fatal("la solicitud cancelada no terminó")
+	}
+
+	close(release)
+	assert.NoError(t, <-follower)
+	assert.Len(t, started, 0)
+}
+
+func TestCache_SingleflightLeaderDeadline(t *testing.T) {
+	server, requests := newCatastroServer(500 * time.Millisecond)
+	defer server.Close()
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCache(NewMemoryCache(), time.Hour, time.Minute))
+
+	// La solicitud que inicia la consulta termina con su propio plazo, sin esperar al SRI
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	begin := time.Now()
+	_, err := service.GetContributorsContext(ctx, "0690000512001")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, ErrHTTPRequest)
+	assert.Less(t, time.Since(begin), 400*time.Millisecond)
+
+	// La consulta compartida continúa y su resultado queda en la caché
+	contributors, err := service.GetContributorsContext(context.Background(), "0690000512001")
+	require.NoError(t, err)
+	assert.Len(t, contributors, 1)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestCache_SingleflightFollowerCanceled(t *testing.T) {
+	server, started, release := newBlockingServer(t)
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCache(NewMemoryCache(), time.Hour, time.Minute))
+
+	leader := make(chan error, 1)
+	go func() {
+		_, err := service.GetContributorsContext(context.Background(), "0690000512001")
+		leader <- err
+	}()
+	<-started
+	waitFlight(t, &service.flight, service.contributorURL("/ConsolidadoContribuyente/obtenerPorNumerosRuc?&ruc=%s", "0690000512001"))
+
+	// La solicitud que espera termina con su propio contexto, sin esperar al SRI
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := service.GetContributorsContext(ctx, "0690000512001")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, err, ErrHTTPRequest)
+
+	close(release)
+	assert.NoError(t, <-leader)
+}
+
+func TestFlight_Panic(t *testing.T) {
+	var f flight
+	release := make(chan struct{})
+
+	leader := make(chan error, 1)
+	go func() {
+		_, err := f.do(context.Background(), "clave", func(ctx context.Context) ([]byte, error) {
+			<-release
+			panic("fallo")
+		})
+		leader <- err
+	}()
+	waitFlight(t, &f, "clave")
+
+	follower := make(chan error, 1)
+	go func() {
+		_, err := f.do(context.Background(), "clave", func(ctx context.Context) ([]byte, error) {
+			return nil, nil
+		})
+		follower <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	for _, result := range []chan error{leader, follower} {
+		select {
+		case err := <-result:
+			assert.ErrorIs(t, err, errFlightAborted)
+		case <-time.After(time.Second):
+			t.Fatal("la solicitud no terminó")
+		}
+	}
+}
+
+func TestCache_FetchedAt(t *testing.T) {
+	server, requests := newCatastroServer(0)
+	defer server.Close()
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithCache(NewMemoryCache(), time.Hour, time.Minute))
+
+	before := time.Now()
+	_, source, err := service.GetContributorsSource(context.Background(), "0690000512001")
+	require.NoError(t, err)
+	assert.Equal(t, SourceSRIOnline, source.Name)
+	assert.WithinRange(t, source.AsOf, before, time.Now())
+
+	time.Sleep(10 * time.Millisecond)
+
+	// La respuesta almacenada conserva la fecha en que se obtuvo del SRI.
+	_, cached, err := service.GetContributorsSource(context.Background(), "0690000512001")
+	require.NoError(t, err)
+	assert.True(t, source.AsOf.Equal(cached.AsOf))
+	assert.Equal(t, int32(1), requests.Load())
+}
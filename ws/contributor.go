@@ -1,22 +1,25 @@
 package ws
 
 import (
+	"context"
+	"strings"
+
 	"github.com/pinzlab/sricore/sri"
 )
 
 // ContributorDates contiene las fechas clave relacionadas con el estado del contribuyente.
 type ContributorDates struct {
 	// StartDate: Fecha en la que el contribuyente inició sus actividades económicas.
-	StartDate string `json:"fechaInicioActividades"`
+	StartDate Date `json:"fechaInicioActividades"`
 
 	// CancellationDate: Fecha en la que el contribuyente cesó sus actividades o fue cancelado.
-	CancellationDate string `json:"fechaCese"`
+	CancellationDate Date `json:"fechaCese"`
 
 	// RestartDate: Fecha en la que el contribuyente reinició sus actividades luego de un cese.
-	RestartDate string `json:"fechaReinicioActividades"`
+	RestartDate Date `json:"fechaReinicioActividades"`
 
 	// UpdateDate: Fecha de la última actualización de los datos del contribuyente.
-	UpdateDate string `json:"fechaActualizacion"`
+	UpdateDate Date `json:"fechaActualizacion"`
 }
 
 // Representative representa a un representante legal del contribuyente.
@@ -37,19 +40,18 @@ type Contributor struct {
 	BusinessName string `json:"razonSocial"`
 
 	// Status: Estado actual del contribuyente en la base de datos del SRI.
-	Status string `json:"estadoContribuyenteRuc"`
+	Status ContributorStatus `json:"estadoContribuyenteRuc"`
 
 	// EconomicActivity: Descripción de la actividad económica principal del contribuyente.
 	EconomicActivity string `json:"actividadEconomicaPrincipal"`
 
 	// Type: Tipo de contribuyente según el SRI.
-	Type string `json:"tipoContribuyente"`
+	Type ContributorType `json:"tipoContribuyente"`
 
-	// Regime: Régimen fiscal bajo el cual está registrado el contribuyente.
-	// Ejemplos: "Régimen general", "Régimen simplificado", etc.
-	Regime string `json:"regimen"`
+	// Regime: Régimen fiscal bajo el cual está registrado el contribuyente (GENERAL o RIMPE).
+	Regime Regime `json:"regimen"`
 
-	// Category: Categoría del contribuyente (si aplica).
+	// Category: Categoría del contribuyente (si aplica), por ejemplo la categoría RIMPE.
 	Category *string `json:"categoria"`
 
 	// MustKeepAccounting: Indica si el contribuyente está obligado a llevar contabilidad.
@@ -103,9 +105,14 @@ type Establishment struct {
 // Este endpoint en parte del API oficial del SRI, pero no están documentados públicamente.
 // Su uso puede estar sujeto a cambios o restricciones sin previo aviso.
 func (s *SRIOnline) CheckRUC(ruc string) (bool, error) {
+	return s.CheckRUCContext(context.Background(), ruc)
+}
+
+// CheckRUCContext es como CheckRUC, pero respeta la cancelación y el plazo del contexto.
+func (s *SRIOnline) CheckRUCContext(ctx context.Context, ruc string) (bool, error) {
 	url := s.contributorURL("/ConsolidadoContribuyente/existePorNumeroRuc?numeroRuc=%s", ruc)
 
-	return get[bool](s.client, url)
+	return cached(ctx, s, "CheckRUC", url, func(exists bool) bool { return !exists })
 }
 
 // GetContributors obtiene la información de un contribuyente por su número de RUC.
@@ -113,9 +120,31 @@ func (s *SRIOnline) CheckRUC(ruc string) (bool, error) {
 // Este endpoint en parte del API oficial del SRI, pero no están documentados públicamente.
 // Su uso puede estar sujeto a cambios o restricciones sin previo aviso.
 func (s *SRIOnline) GetContributors(ruc string) ([]*Contributor, error) {
-	url := s.contributorURL("/ConsolidadoContribuyente/obtenerPorNumerosRuc?&ruc=%s", ruc)
+	return s.GetContributorsContext(context.Background(), ruc)
+}
+
+// GetContributorsContext es como GetContributors, pero respeta la cancelación y el
+// plazo del contexto.
+func (s *SRIOnline) GetContributorsContext(ctx context.Context, ruc string) ([]*Contributor, error) {
+	contributors, _, err := s.GetContributorsSource(ctx, ruc)
+	return contributors, err
+}
 
-	return get[[]*Contributor](s.client, url)
+// GetContributorsSource es como GetContributorsContext, pero también devuelve la fecha
+// en que se obtuvieron los datos del SRI; con la caché activa, la de la respuesta
+// almacenada. Implementa DatedLookup.
+func (s *SRIOnline) GetContributorsSource(ctx context.Context, ruc string) ([]*Contributor, DataSource, error) {
+	url := s.contributorsURL(ruc)
+
+	contributors, fetchedAt, err := cachedAt(ctx, s, "GetContributors", url, isEmpty[*Contributor])
+
+	return contributors, DataSource{Name: SourceSRIOnline, AsOf: fetchedAt}, err
+}
+
+// contributorsURL construye la URL de obtenerPorNumerosRuc, que acepta varios RUC
+// separados por comas.
+func (s *SRIOnline) contributorsURL(rucs ...string) string {
+	return s.contributorURL("/ConsolidadoContribuyente/obtenerPorNumerosRuc?&ruc=%s", strings.Join(rucs, ","))
 }
 
 // GetEstablishments obtiene la información de los establecimientos asociados a un RUC.
@@ -123,7 +152,13 @@ func (s *SRIOnline) GetContributors(ruc string) ([]*Contributor, error) {
 // Este endpoint en parte del API oficial del SRI, pero no están documentados públicamente.
 // Su uso puede estar sujeto a cambios o restricciones sin previo aviso.
 func (s *SRIOnline) GetEstablishments(ruc string) ([]*Establishment, error) {
+	return s.GetEstablishmentsContext(context.Background(), ruc)
+}
+
+// GetEstablishmentsContext es como GetEstablishments, pero respeta la cancelación y el
+// plazo del contexto.
+func (s *SRIOnline) GetEstablishmentsContext(ctx context.Context, ruc string) ([]*Establishment, error) {
 	url := s.contributorURL("/Establecimiento/consultarPorNumeroRuc?numeroRuc=%s", ruc)
 
-	return get[[]*Establishment](s.client, url)
+	return cached(ctx, s, "GetEstablishments", url, isEmpty[*Establishment])
 }
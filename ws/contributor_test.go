@@ -1,56 +1,68 @@
-package ws
+package ws_test
 
 import (
-	"net/http"
 	"testing"
 
+	"github.com/pinzlab/sricore/sritest"
+	"github.com/pinzlab/sricore/ws"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // RUC de la empresa electrica riobamba S.A.
 const eersaRuc = "0690000512001"
 
-// service es una instancia global del cliente SRIOnline utilizada.
-var service *SRIOnline
-
-func init() {
-	service = NewSRIOnline()
+// newTestService inicia un servidor sritest con la empresa eléctrica registrada.
+func newTestService(t *testing.T) (*ws.SRIOnline, *sritest.Server) {
+	server := sritest.NewServer()
+	t.Cleanup(server.Close)
+
+	name := "EERSA"
+	server.AddContributor(&ws.Contributor{
+		Ruc:          eersaRuc,
+		BusinessName: "EMPRESA ELECTRICA RIOBAMBA SA",
+		Status:       "ACTIVO",
+	}, &ws.Establishment{
+		TradeName: &name,
+		Status:    "ABIERTO",
+		Number:    "001",
+		IsMain:    true,
+	})
+
+	return ws.NewSRIOnline(ws.WithHTTPClient(server.Client())), server
 }
 
 func TestCheckRUC(t *testing.T) {
+	service, _ := newTestService(t)
 
-	url := service.contributorURL("/ConsolidadoContribuyente/existePorNumeroRuc?numeroRuc=%s", eersaRuc)
-
-	res, err := service.client.Get(url)
-
-	assert.NoError(t, err)
-	defer res.Body.Close()
-
-	assert.Equal(t, http.StatusOK, res.StatusCode)
+	exists, err := service.CheckRUC(eersaRuc)
+	require.NoError(t, err)
+	assert.True(t, exists)
 
+	exists, err = service.CheckRUC("0601234560001")
+	require.NoError(t, err)
+	assert.False(t, exists)
 }
-func TestGetContributors(t *testing.T) {
-
-	url := service.contributorURL("/ConsolidadoContribuyente/obtenerPorNumerosRuc?&ruc=%s", eersaRuc)
 
-	res, err := service.client.Get(url)
-
-	assert.NoError(t, err)
-	defer res.Body.Close()
-
-	assert.Equal(t, http.StatusOK, res.StatusCode)
+func TestGetContributors(t *testing.T) {
+	service, _ := newTestService(t)
 
+	contributors, err := service.GetContributors(eersaRuc)
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+	assert.Equal(t, "EMPRESA ELECTRICA RIOBAMBA SA", contributors[0].BusinessName)
 }
 
 func TestGetEstablishments(t *testing.T) {
+	service, server := newTestService(t)
 
-	url := service.contributorURL("/Establecimiento/consultarPorNumeroRuc?numeroRuc=%s", eersaRuc)
-
-	res, err := service.client.Get(url)
-
-	assert.NoError(t, err)
-	defer res.Body.Close()
-
-	assert.Equal(t, http.StatusOK, res.StatusCode)
+	establishments, err := service.GetEstablishments(eersaRuc)
+	require.NoError(t, err)
+	require.Len(t, establishments, 1)
+	assert.Equal(t, "001", establishments[0].Number)
+	assert.True(t, bool(establishments[0].IsMain))
 
+	server.Outage(1)
+	_, err = service.GetEstablishments(eersaRuc)
+	assert.ErrorIs(t, err, ws.ErrHTTPStatus)
 }
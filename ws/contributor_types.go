@@ -0,0 +1,211 @@
+package ws
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// ContributorStatus es el estado de un contribuyente en el RUC.
+type ContributorStatus string
+
+const (
+	// ContributorActive indica que el contribuyente está activo.
+	ContributorActive ContributorStatus = "ACTIVO"
+
+	// ContributorSuspended indica que el RUC está suspendido.
+	ContributorSuspended ContributorStatus = "SUSPENDIDO"
+
+	// ContributorPassive indica que el RUC está pasivo (cancelado).
+	ContributorPassive ContributorStatus = "PASIVO"
+)
+
+// ContributorType es el tipo de contribuyente según el SRI.
+type ContributorType string
+
+const (
+	// NaturalPerson es una persona natural.
+	NaturalPerson ContributorType = "PERSONA NATURAL"
+
+	// Company es una sociedad.
+	Company ContributorType = "SOCIEDAD"
+)
+
+// Regime es el régimen tributario del contribuyente.
+type Regime string
+
+const (
+	// RegimeGeneral es el régimen general.
+	RegimeGeneral Regime = "GENERAL"
+
+	// RegimeRIMPE es el Régimen Simplificado para Emprendedores y Negocios Populares.
+	RegimeRIMPE Regime = "RIMPE"
+)
+
+// RIMPECategory es la categoría de un contribuyente del régimen RIMPE.
+type RIMPECategory string
+
+const (
+	// RIMPEEntrepreneur es la categoría de emprendedores.
+	RIMPEEntrepreneur RIMPECategory = "EMPRENDEDOR"
+
+	// RIMPEPopularBusiness es la categoría de negocios populares.
+	RIMPEPopularBusiness RIMPECategory = "NEGOCIO POPULAR"
+)
+
+// Estados de un establecimiento.
+const (
+	// EstablishmentOpen indica que el establecimiento está abierto.
+	EstablishmentOpen = "ABIERTO"
+
+	// EstablishmentClosed indica que el establecimiento está cerrado.
+	EstablishmentClosed = "CERRADO"
+)
+
+// catastroDateFormats son los formatos de fecha usados por el catastro del SRI.
+var catastroDateFormats = []string{
+	"2006-01-02 15:04:05.0",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+	"02/01/2006",
+	time.RFC3339,
+}
+
+// ecuador es la zona horaria continental de Ecuador, usada por el catastro del SRI.
+var ecuador = time.FixedZone("ECT", -5*60*60)
+
+// Date es una fecha del catastro del SRI. Las fechas vacías o nulas se representan con
+// el valor cero de time.Time.
+//
+// Una fecha con un formato no reconocido no produce un error al deserializar la
+// respuesta: se conserva en Raw con el valor cero de time.Time y se notifica mediante
+// Hooks.OnSchemaDrift.
+type Date struct {
+	time.Time
+
+	// Raw: Valor original de la fecha cuando no tiene un formato reconocido.
+	Raw string
+}
+
+// ParseDate interpreta una fecha del catastro del SRI en cualquiera de sus formatos
+// ("2006-01-02 15:04:05.0", "2006-01-02", "02/01/2006", ...). Una cadena vacía
+// devuelve la fecha cero.
+func ParseDate(value string) (Date, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return Date{}, nil
+	}
+
+	var err error
+	for _, format := range catastroDateFormats {
+		var date time.Time
+		if date, err = time.ParseInLocation(format, value, ecuador); err == nil {
+			return Date{Time: date}, nil
+		}
+	}
+
+	return Date{}, err
+}
+
+// UnmarshalJSON implementa el deserializado de las fechas del catastro. Los valores
+// que no son fechas reconocidas se conservan en Raw.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	*d = Date{}
+
+	var value *string
+	if err := json.Unmarshal(data, &value); err != nil {
+		d.Raw = string(data)
+		return nil
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	date, err := ParseDate(*value)
+	if err != nil {
+		d.Raw = *value
+		return nil
+	}
+
+	*d = date
+	return nil
+}
+
+// Valid indica si la fecha está vacía o tiene un formato reconocido.
+func (d Date) Valid() bool {
+	return d.Raw == ""
+}
+
+// MarshalJSON serializa la fecha con el formato del catastro. Una fecha no reconocida
+// se serializa con su valor original.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if !d.Valid() {
+		return json.Marshal(d.Raw)
+	}
+
+	if d.IsZero() {
+		return json.Marshal("")
+	}
+
+	return json.Marshal(d.In(ecuador).Format(catastroDateFormats[0]))
+}
+
+// IsActive indica si el contribuyente está activo.
+func (c *Contributor) IsActive() bool {
+	return normalize(string(c.Status)) == string(ContributorActive)
+}
+
+// IsRIMPE indica si el contribuyente pertenece al régimen RIMPE.
+func (c *Contributor) IsRIMPE() bool {
+	return strings.HasPrefix(normalize(string(c.Regime)), string(RegimeRIMPE))
+}
+
+// RIMPECategory devuelve la categoría RIMPE del contribuyente, o una cadena vacía si no
+// pertenece al régimen o no se informa la categoría.
+func (c *Contributor) RIMPECategory() RIMPECategory {
+	if !c.IsRIMPE() {
+		return ""
+	}
+
+	value := normalize(string(c.Regime))
+	if c.Category != nil {
+		value += " " + normalize(*c.Category)
+	}
+
+	switch {
+	case strings.Contains(value, string(RIMPEPopularBusiness)):
+		return RIMPEPopularBusiness
+	case strings.Contains(value, string(RIMPEEntrepreneur)):
+		return RIMPEEntrepreneur
+	default:
+		return ""
+	}
+}
+
+// IsNaturalPerson indica si el contribuyente es una persona natural.
+func (c *Contributor) IsNaturalPerson() bool {
+	return normalize(string(c.Type)) == string(NaturalPerson)
+}
+
+// LegalRepresentative devuelve el primer representante legal registrado, o nil si no
+// hay ninguno (por ejemplo, en personas naturales).
+func (c *Contributor) LegalRepresentative() *Representative {
+	for _, representative := range c.Representatives {
+		if representative != nil {
+			return representative
+		}
+	}
+
+	return nil
+}
+
+// IsOpen indica si el establecimiento está abierto.
+func (e *Establishment) IsOpen() bool {
+	return normalize(e.Status) == EstablishmentOpen
+}
+
+// normalize elimina espacios y convierte a mayúsculas un valor del catastro.
+func normalize(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
@@ -0,0 +1,94 @@
+package ws
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const contributorJSON = `{
+	"numeroRuc": "0601234560001",
+	"razonSocial": "PEREZ JUAN",
+	"estadoContribuyenteRuc": "ACTIVO",
+	"tipoContribuyente": "PERSONA NATURAL",
+	"regimen": "RIMPE",
+	"categoria": "NEGOCIO POPULAR",
+	"informacionFechasContribuyente": {
+		"fechaInicioActividades": "2008-09-11 00:00:00.0",
+		"fechaCese": "",
+		"fechaReinicioActividades": null,
+		"fechaActualizacion": "2023-05-10 10:15:20.0"
+	},
+	"representantesLegales": null
+}`
+
+func TestContributor_Types(t *testing.T) {
+	var contributor Contributor
+	require.NoError(t, json.Unmarshal([]byte(contributorJSON), &contributor))
+
+	assert.Equal(t, ContributorActive, contributor.Status)
+	assert.True(t, contributor.IsActive())
+	assert.True(t, contributor.IsNaturalPerson())
+	assert.True(t, contributor.IsRIMPE())
+	assert.Equal(t, RIMPEPopularBusiness, contributor.RIMPECategory())
+	assert.Nil(t, contributor.LegalRepresentative())
+
+	dates := contributor.TaxpayerDates
+	assert.Equal(t, time.Date(2008, 9, 11, 5, 0, 0, 0, time.UTC), dates.StartDate.UTC())
+	assert.Equal(t, time.Date(2023, 5, 10, 15, 15, 20, 0, time.UTC), dates.UpdateDate.UTC())
+	assert.True(t, dates.CancellationDate.IsZero())
+	assert.True(t, dates.RestartDate.IsZero())
+
+	data, err := json.Marshal(dates)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"fechaInicioActividades": "2008-09-11 00:00:00.0",
+		"fechaCese": "",
+		"fechaReinicioActividades": "",
+		"fechaActualizacion": "2023-05-10 10:15:20.0"
+	}`, string(data))
+}
+
+func TestContributor_Helpers(t *testing.T) {
+	category := "emprendedor"
+	contributor := &Contributor{
+		Status:   ContributorSuspended,
+		Type:     Company,
+		Regime:   "RIMPE",
+		Category: &category,
+		Representatives: []*Representative{
+			{Dni: "0601234560", Name: "PEREZ JUAN"},
+		},
+	}
+
+	assert.False(t, contributor.IsActive())
+	assert.False(t, contributor.IsNaturalPerson())
+	assert.Equal(t, RIMPEEntrepreneur, contributor.RIMPECategory())
+	assert.Equal(t, "0601234560", contributor.LegalRepresentative().Dni)
+
+	contributor.Regime = RegimeGeneral
+	assert.False(t, contributor.IsRIMPE())
+	assert.Empty(t, contributor.RIMPECategory())
+
+	assert.True(t, (&Establishment{Status: " abierto"}).IsOpen())
+	assert.False(t, (&Establishment{Status: EstablishmentClosed}).IsOpen())
+}
+
+func TestDate_Invalid(t *testing.T) {
+	var date Date
+	require.NoError(t, json.Unmarshal([]byte(`"11 de septiembre"`), &date))
+	assert.True(t, date.IsZero())
+	assert.False(t, date.Valid())
+	assert.Equal(t, "11 de septiembre", date.Raw)
+
+	data, err := json.Marshal(date)
+	require.NoError(t, err)
+	assert.JSONEq(t, `"11 de septiembre"`, string(data))
+
+	require.NoError(t, json.Unmarshal([]byte(`"11/09/2008"`), &date))
+	assert.True(t, date.Valid())
+	assert.Equal(t, 2008, date.Year())
+}
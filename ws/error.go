@@ -0,0 +1,71 @@
+package ws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxErrorBody es la longitud máxima del cuerpo de la respuesta que se conserva en Error.
+const maxErrorBody = 512
+
+// Error es un error de comunicación con los servicios del SRI. Coincide con el error
+// centinela que lo clasifica (ErrHTTPRequest, ErrHTTPStatus, ErrReadBody,
+// ErrJSONUnmarshal, ErrXMLUnmarshal o ErrSOAPFault) y con su causa mediante errors.Is.
+type Error struct {
+	// Op: Operación del cliente que falló (por ejemplo, "CheckRUC").
+	Op string
+
+	// URL: URL de la solicitud.
+	URL string
+
+	// StatusCode: Estado HTTP de la respuesta, o 0 si no hubo respuesta.
+	StatusCode int
+
+	// Body: Cuerpo de la respuesta, truncado a 512 bytes.
+	Body string
+
+	// Kind: Error centinela que clasifica el error.
+	Kind error
+
+	// Err: Causa del error (si aplica).
+	Err error
+}
+
+// newError crea un Error truncando el cuerpo de la respuesta.
+func newError(op, url string, status int, body []byte, kind, cause error) *Error {
+	if len(body) > maxErrorBody {
+		body = body[:maxErrorBody]
+	}
+
+	return &Error{Op: op, URL: url, StatusCode: status, Body: string(body), Kind: kind, Err: cause}
+}
+
+// Error implementa la interfaz error.
+func (e *Error) Error() string {
+	var b strings.Builder
+
+	if e.Op != "" {
+		b.WriteString(e.Op + ": ")
+	}
+
+	b.WriteString(e.Kind.Error())
+
+	if e.StatusCode != 0 {
+		fmt.Fprintf(&b, " (HTTP %d)", e.StatusCode)
+	}
+
+	if e.Err != nil {
+		b.WriteString(": " + e.Err.Error())
+	}
+
+	return b.String()
+}
+
+// Unwrap devuelve el error centinela y la causa, para errors.Is y errors.As.
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+
+	return []error{e.Kind, e.Err}
+}
@@ -0,0 +1,67 @@
+package ws
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(strings.Repeat("x", 1000)))
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithLogger(logger))
+
+	_, err := service.GetContributors("0690000512001")
+	require.ErrorIs(t, err, ErrHTTPStatus)
+
+	var httpErr *Error
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, "GetContributors", httpErr.Op)
+	assert.Equal(t, http.StatusBadGateway, httpErr.StatusCode)
+	assert.Len(t, httpErr.Body, maxErrorBody)
+	assert.Equal(t, "GetContributors: El SRI respondió con un estado no exitoso (HTTP 502)", httpErr.Error())
+
+	assert.Contains(t, logs.String(), "level=WARN")
+	assert.Contains(t, logs.String(), "op=GetContributors")
+}
+
+func TestError_SOAPFault(t *testing.T) {
+	server := newReceptionServer(t)
+	defer server.Close()
+
+	service := NewSRIOnline(WithVouchersURL(sri.EnvTest, server.URL))
+
+	_, err := service.ValidateVoucher(sri.EnvTest, []byte("<error/>"))
+	require.ErrorIs(t, err, ErrSOAPFault)
+
+	var httpErr *Error
+	require.ErrorAs(t, err, &httpErr)
+	assert.Equal(t, "ValidateVoucher", httpErr.Op)
+	assert.EqualError(t, httpErr.Err, "Error interno")
+	assert.Contains(t, httpErr.Body, "<soap:Fault>")
+}
+
+func TestError_Request(t *testing.T) {
+	service := NewSRIOnline(WithBaseURL("http://127.0.0.1:1"))
+	_, err := service.CheckRUC("0690000512001")
+	assert.ErrorIs(t, err, ErrHTTPRequest)
+
+	var httpErr *Error
+	require.ErrorAs(t, err, &httpErr)
+	assert.Zero(t, httpErr.StatusCode)
+	assert.NotNil(t, httpErr.Err)
+}
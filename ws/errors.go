@@ -7,4 +7,16 @@ var (
 	ErrHTTPStatus    = errors.New("El SRI respondió con un estado no exitoso")
 	ErrReadBody      = errors.New("No se pudo leer el cuerpo de la respuesta")
 	ErrJSONUnmarshal = errors.New("No se pudo procesar la respuesta JSON")
+	ErrXMLUnmarshal  = errors.New("No se pudo procesar la respuesta XML")
+	ErrSOAPFault     = errors.New("El servicio web del SRI respondió con un error SOAP")
+	ErrInvalidEnv    = errors.New("El ambiente indicado no es válido")
+	ErrCircuitOpen   = errors.New("El servicio del SRI no está disponible")
+
+	ErrContributorNotFound   = errors.New("El RUC no está registrado en el SRI")
+	ErrInvalidEstablishment  = errors.New("El código de establecimiento no es válido")
+	ErrEstablishmentNotFound = errors.New("El establecimiento no está registrado en el RUC")
+	ErrEstablishmentNotOpen  = errors.New("El establecimiento no está abierto")
+
+	ErrInvalidPlate    = errors.New("La placa, CAMV o CPN no es válido")
+	ErrVehicleNotFound = errors.New("El vehículo no está registrado en el SRI")
 )
@@ -0,0 +1,103 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pinzlab/sricore/id"
+	"github.com/pinzlab/sricore/sri"
+)
+
+// EstablishmentValidator verifica que el establecimiento de un comprobante esté
+// registrado y abierto en el RUC del emisor antes de emitirlo, ya que el SRI devuelve
+// los comprobantes de establecimientos cerrados (mensaje 56).
+//
+// El catastro consultado determina el modo de validación: un *SRIOnline configurado con
+// WithCache evita consultar al SRI en cada comprobante, y un índice local permite validar
+// sin conexión.
+type EstablishmentValidator struct {
+	lookup ContributorLookup
+}
+
+// NewEstablishmentValidator crea un validador que consulta el catastro indicado.
+func NewEstablishmentValidator(lookup ContributorLookup) *EstablishmentValidator {
+	return &EstablishmentValidator{lookup: lookup}
+}
+
+// ValidateAccessKey valida el establecimiento de la clave de acceso de un comprobante.
+func (v *EstablishmentValidator) ValidateAccessKey(ctx context.Context, key *sri.AccessKey) (*Establishment, error) {
+	return v.Validate(ctx, key.RUC, key.Establishment)
+}
+
+// Validate verifica que el establecimiento esté registrado y abierto en el RUC indicado
+// y lo devuelve.
+//
+// Returns:
+//   - El establecimiento registrado en el SRI.
+//   - ErrInvalidEstablishment o el error de id.IsRUC si los datos no son válidos,
+//     ErrContributorNotFound si el RUC no existe, ErrEstablishmentNotFound si el RUC no
+//     tiene el establecimiento, ErrEstablishmentNotOpen si no está abierto, o el error
+//     de la consulta al catastro.
+func (v *EstablishmentValidator) Validate(ctx context.Context, ruc, number string) (*Establishment, error) {
+	if err := id.IsRUC(ruc); err != nil {
+		return nil, err
+	}
+
+	code, err := establishmentCode(number)
+	if err != nil {
+		return nil, err
+	}
+
+	establishments, err := v.lookup.GetEstablishmentsContext(ctx, ruc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(establishments) == 0 {
+		exists, err := v.lookup.CheckRUCContext(ctx, ruc)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			return nil, fmt.Errorf("%w: %s", ErrContributorNotFound, ruc)
+		}
+	}
+
+	for _, establishment := range establishments {
+		if establishment == nil {
+			continue
+		}
+
+		if current, err := establishmentCode(establishment.Number); err != nil || current != code {
+			continue
+		}
+
+		if !establishment.IsOpen() {
+			return establishment, fmt.Errorf("%w: %s-%s está %s", ErrEstablishmentNotOpen, ruc, number, establishment.Status)
+		}
+
+		return establishment, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s-%s", ErrEstablishmentNotFound, ruc, number)
+}
+
+// establishmentCode interpreta un código de establecimiento de 1 a 3 dígitos ("001" o "1").
+func establishmentCode(number string) (int, error) {
+	number = strings.TrimSpace(number)
+
+	code, err := strconv.Atoi(number)
+	if err != nil || len(number) > 3 || code < 1 || strings.ContainsFunc(number, notDigit) {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidEstablishment, number)
+	}
+
+	return code, nil
+}
+
+// notDigit indica si el carácter no es un dígito.
+func notDigit(r rune) bool {
+	return r < '0' || r > '9'
+}
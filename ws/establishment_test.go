@@ -0,0 +1,77 @@
+package ws_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pinzlab/sricore/id"
+	"github.com/pinzlab/sricore/sri"
+	"github.com/pinzlab/sricore/ws"
+)
+
+func TestEstablishmentValidator(t *testing.T) {
+	lookup := &staticLookup{
+		contributors: map[string]*ws.Contributor{
+			eersaRuc:        {Ruc: eersaRuc},
+			"1790011674001": {Ruc: "1790011674001"},
+		},
+		establishments: map[string][]*ws.Establishment{
+			eersaRuc: {
+				{Number: "001", Status: ws.EstablishmentOpen},
+				{Number: "002", Status: ws.EstablishmentClosed},
+			},
+		},
+	}
+	validator := ws.NewEstablishmentValidator(lookup)
+	ctx := context.Background()
+
+	establishment, err := validator.ValidateAccessKey(ctx, &sri.AccessKey{RUC: eersaRuc, Establishment: "001"})
+	require.NoError(t, err)
+	assert.Equal(t, "001", establishment.Number)
+
+	establishment, err = validator.Validate(ctx, eersaRuc, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "001", establishment.Number)
+
+	establishment, err = validator.Validate(ctx, eersaRuc, "002")
+	assert.ErrorIs(t, err, ws.ErrEstablishmentNotOpen)
+	assert.EqualError(t, err, ws.ErrEstablishmentNotOpen.Error()+": "+eersaRuc+"-002 está CERRADO")
+	assert.Equal(t, "002", establishment.Number)
+
+	_, err = validator.Validate(ctx, eersaRuc, "003")
+	assert.ErrorIs(t, err, ws.ErrEstablishmentNotFound)
+
+	_, err = validator.Validate(ctx, "1790011674001", "001")
+	assert.ErrorIs(t, err, ws.ErrEstablishmentNotFound)
+
+	_, err = validator.Validate(ctx, "1791256115001", "001")
+	assert.ErrorIs(t, err, ws.ErrContributorNotFound)
+
+	for _, number := range []string{"", "000", "0001", "+01", "A01"} {
+		_, err = validator.Validate(ctx, eersaRuc, number)
+		assert.ErrorIs(t, err, ws.ErrInvalidEstablishment, number)
+	}
+
+	_, err = validator.Validate(ctx, "0690000512000", "001")
+	assert.Equal(t, id.IsRUC("0690000512000"), err)
+}
+
+func TestEstablishmentValidator_Cached(t *testing.T) {
+	_, server := newTestService(t)
+	service := ws.NewSRIOnline(
+		ws.WithHTTPClient(server.Client()),
+		ws.WithCache(ws.NewMemoryCache(), time.Minute, time.Minute),
+	)
+	validator := ws.NewEstablishmentValidator(service)
+
+	for range 3 {
+		_, err := validator.Validate(context.Background(), eersaRuc, "001")
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 1, server.Requests())
+}
@@ -0,0 +1,165 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+)
+
+// probeRUC es el RUC consultado para comprobar la disponibilidad del catastro (el del
+// propio SRI).
+const probeRUC = "1760013210001"
+
+// ServiceHealth es el resultado de comprobar la disponibilidad de un servicio.
+type ServiceHealth struct {
+	// Service: Servicio comprobado.
+	Service Service
+
+	// Status: Disponibilidad del servicio tras la comprobación.
+	Status HealthStatus
+
+	// Latency: Duración de la comprobación.
+	Latency time.Duration
+
+	// Err: Error de la comprobación (si aplica).
+	Err error
+}
+
+// HealthReport es el resultado de comprobar la disponibilidad de los servicios del SRI.
+type HealthReport struct {
+	// CheckedAt: Fecha de la comprobación.
+	CheckedAt time.Time
+
+	// Services: Resultado de cada servicio, en el orden del monitor.
+	Services []ServiceHealth
+}
+
+// Status devuelve la disponibilidad de un servicio en el reporte, o StatusDown si no fue
+// comprobado.
+func (r *HealthReport) Status(service Service) HealthStatus {
+	for _, health := range r.Services {
+		if health.Service == service {
+			return health.Status
+		}
+	}
+
+	return StatusDown
+}
+
+// HealthMonitor comprueba periódicamente la disponibilidad del catastro y de los servicios
+// web de comprobantes. Si el cliente tiene un CircuitBreaker, cada comprobación actualiza
+// su circuito: una comprobación exitosa lo cierra sin esperar a que una solicitud real
+// lo pruebe, y las fallidas lo abren aunque no haya tráfico.
+type HealthMonitor struct {
+	s        *SRIOnline
+	interval time.Duration
+	services []Service
+
+	mu   sync.Mutex
+	last *HealthReport
+}
+
+// NewHealthMonitor crea un monitor que comprueba los servicios indicados cada interval.
+// Sin servicios, comprueba el catastro y los comprobantes de ambos ambientes.
+func NewHealthMonitor(s *SRIOnline, interval time.Duration, services ...Service) *HealthMonitor {
+	if len(services) == 0 {
+		services = []Service{ServiceCatastro, ServiceVouchersTest, ServiceVouchersProd}
+	}
+
+	return &HealthMonitor{s: s, interval: interval, services: services}
+}
+
+// Run comprueba los servicios de inmediato y luego cada intervalo, hasta que el contexto
+// se cancele. Devuelve el error del contexto.
+func (m *HealthMonitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check comprueba todos los servicios en paralelo.
+func (m *HealthMonitor) Check(ctx context.Context) *HealthReport {
+	report := &HealthReport{CheckedAt: time.Now(), Services: make([]ServiceHealth, len(m.services))}
+
+	var wg sync.WaitGroup
+	for i, service := range m.services {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Services[i] = m.s.probe(ctx, service)
+		}()
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	m.last = report
+	m.mu.Unlock()
+
+	return report
+}
+
+// Last devuelve el resultado de la última comprobación, o nil si aún no se realizó.
+func (m *HealthMonitor) Last() *HealthReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.last
+}
+
+// probe comprueba la disponibilidad de un servicio con una sola solicitud GET, sin
+// reintentos ni caché. La comprobación se envía aunque el circuito esté abierto.
+func (s *SRIOnline) probe(ctx context.Context, service Service) ServiceHealth {
+	url := s.contributorURL("/ConsolidadoContribuyente/existePorNumeroRuc?numeroRuc=%s", probeRUC)
+	switch service {
+	case ServiceVouchersTest:
+		url, _ = s.voucherURL(sri.EnvTest, "/RecepcionComprobantesOffline?wsdl")
+	case ServiceVouchersProd:
+		url, _ = s.voucherURL(sri.EnvProd, "/RecepcionComprobantesOffline?wsdl")
+	}
+
+	start := time.Now()
+	var status int
+	var failure error
+	err := s.observe(ctx, "HealthCheck", http.MethodGet, url, func(ctx context.Context) error {
+		var body []byte
+		if status, body, failure = s.attempt(ctx, "HealthCheck", http.MethodGet, url, "", nil); failure != nil {
+			return failure
+		}
+
+		if status != http.StatusOK {
+			return s.fail("HealthCheck", url, status, body, ErrHTTPStatus, nil)
+		}
+
+		return nil
+	})
+
+	health := ServiceHealth{Service: service, Latency: time.Since(start), Err: err}
+
+	switch {
+	case s.breaker != nil:
+		// La comprobación no es la solicitud de prueba del circuito semiabierto.
+		s.breaker.record(ctx, service, false, status, failure)
+		health.Status = s.breaker.Status(service)
+	case outage(status, failure):
+		health.Status = StatusDown
+	}
+
+	// El servicio responde, pero no como se espera.
+	if err != nil && health.Status == StatusUp {
+		health.Status = StatusDegraded
+	}
+
+	return health
+}
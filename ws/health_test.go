@@ -0,0 +1,112 @@
+package ws_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/pinzlab/sricore/sritest"
+	"github.com/pinzlab/sricore/ws"
+)
+
+func TestHealthMonitor_Check(t *testing.T) {
+	service, _ := newTestService(t)
+
+	monitor := ws.NewHealthMonitor(service, time.Minute)
+	assert.Nil(t, monitor.Last())
+
+	report := monitor.Check(context.Background())
+	require.Len(t, report.Services, 3)
+	for _, health := range report.Services {
+		require.NoError(t, health.Err, health.Service)
+		assert.Equal(t, ws.StatusUp, health.Status)
+	}
+	assert.Equal(t, ws.ServiceCatastro, report.Services[0].Service)
+	assert.Same(t, report, monitor.Last())
+}
+
+func TestHealthMonitor_Outage(t *testing.T) {
+	server, breaker := newBreakerService(t)
+	server.Outage(-1)
+
+	service := ws.NewSRIOnline(ws.WithHTTPClient(server.Client()), ws.WithCircuitBreaker(breaker))
+	monitor := ws.NewHealthMonitor(service, time.Minute, ws.ServiceCatastro)
+
+	report := monitor.Check(context.Background())
+	assert.Equal(t, ws.StatusDown, report.Status(ws.ServiceCatastro))
+	require.ErrorIs(t, report.Services[0].Err, ws.ErrHTTPStatus)
+
+	// Requests fail fast while the SRI is down.
+	requests := server.Requests()
+	_, err := service.CheckRUC(eersaRuc)
+	require.ErrorIs(t, err, ws.ErrCircuitOpen)
+	assert.Equal(t, requests, server.Requests())
+
+	// The monitor probes even with the circuit open and closes it on recovery.
+	server.Restore()
+	report = monitor.Check(context.Background())
+	assert.Equal(t, ws.StatusUp, report.Status(ws.ServiceCatastro))
+
+	exists, err := service.CheckRUC(eersaRuc)
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestHealthMonitor_Vouchers(t *testing.T) {
+	server, breaker := newBreakerService(t)
+
+	service := ws.NewSRIOnline(ws.WithHTTPClient(server.Client()), ws.WithCircuitBreaker(breaker))
+	monitor := ws.NewHealthMonitor(service, time.Minute, ws.VouchersService(sri.EnvProd))
+
+	server.Outage(1)
+	report := monitor.Check(context.Background())
+	assert.Equal(t, ws.StatusDown, report.Status(ws.ServiceVouchersProd))
+
+	// A POS can queue the voucher instead of sending it.
+	assert.False(t, breaker.Available(ws.ServiceVouchersProd))
+	_, err := service.ValidateVoucher(sri.EnvProd, []byte("<factura/>"))
+	require.ErrorIs(t, err, ws.ErrCircuitOpen)
+
+	// The catastro keeps its own circuit.
+	assert.Equal(t, ws.StatusUp, breaker.Status(ws.ServiceCatastro))
+}
+
+func TestHealthMonitor_Run(t *testing.T) {
+	server, breaker := newBreakerService(t)
+	server.Outage(-1)
+
+	var mu sync.Mutex
+	var changes []ws.StatusChange
+	breaker.OnChange(func(change ws.StatusChange) {
+		mu.Lock()
+		defer mu.Unlock()
+		changes = append(changes, change)
+	})
+
+	service := ws.NewSRIOnline(ws.WithHTTPClient(server.Client()), ws.WithCircuitBreaker(breaker))
+	monitor := ws.NewHealthMonitor(service, 5*time.Millisecond, ws.ServiceCatastro)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, monitor.Run(ctx), context.DeadlineExceeded)
+	require.NotNil(t, monitor.Last())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.NotEmpty(t, changes)
+	assert.Equal(t, ws.StatusDown, changes[0].To)
+	assert.Equal(t, ws.ServiceCatastro, changes[0].Service)
+}
+
+// newBreakerService crea un servidor de pruebas y un circuito que se abre con la primera falla.
+func newBreakerService(t *testing.T) (*sritest.Server, *ws.CircuitBreaker) {
+	_, server := newTestService(t)
+
+	return server, ws.NewCircuitBreaker(1, time.Hour)
+}
@@ -1,10 +1,14 @@
 package ws
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
+	"time"
 )
 
 // get realiza una solicitud HTTP GET a la URL indicada y deserializa la respuesta JSON
@@ -12,37 +16,173 @@ import (
 //
 // Parameters:
 //
-//	client: Cliente HTTP para realizar la solicitud.
+//	ctx: Contexto de la solicitud; su cancelación o plazo interrumpen la solicitud.
+//	s: Cliente SRIOnline con la configuración HTTP (cliente, cabeceras y tiempo máximo).
+//	op: Nombre de la operación, usado en los errores y registros.
 //	url: URL para la solicitud GET.
 //
 // Returns:
 //   - Un valor deserializado del tipo indicado (T).
-//   - Un error si ocurre algún problema durante la solicitud o deserialización.
-func get[T any](client *http.Client, url string) (T, error) {
+//   - Un *Error si ocurre algún problema durante la solicitud o deserialización.
+func get[T any](ctx context.Context, s *SRIOnline, op, url string) (T, error) {
 	var result T
 
-	resp, err := client.Get(url)
+	err := s.observe(ctx, op, http.MethodGet, url, func(ctx context.Context) error {
+		body, err := s.fetch(ctx, op, url)
+		if err != nil {
+			return err
+		}
+
+		if result, err = decode[T](s, op, url, body); err != nil {
+			return err
+		}
+		checkSchema(s, op, url, body, result)
+
+		return nil
+	})
+
+	return result, err
+}
+
+// fetch realiza una solicitud HTTP GET y devuelve el cuerpo de una respuesta exitosa.
+func (s *SRIOnline) fetch(ctx context.Context, op, url string) ([]byte, error) {
+	status, body, err := s.exchange(ctx, op, http.MethodGet, url, "", nil, true)
 	if err != nil {
-		log.Printf("GET request error: %v", err)
-		return result, ErrHTTPRequest
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("Unexpected status code from SRI: %d", resp.StatusCode)
-		return result, ErrHTTPStatus
+	if status != http.StatusOK {
+		return nil, s.fail(op, url, status, body, ErrHTTPStatus, nil)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		log.Printf("Failed to read response body: %v", err)
-		return result, ErrReadBody
-	}
+	return body, nil
+}
 
+// decode deserializa el cuerpo JSON de una respuesta exitosa en el tipo especificado.
+func decode[T any](s *SRIOnline, op, url string, body []byte) (T, error) {
+	var result T
 	if err := json.Unmarshal(body, &result); err != nil {
-		log.Printf("Failed to unmarshal JSON: %v", err)
-		return result, ErrJSONUnmarshal
+		return result, s.fail(op, url, http.StatusOK, body, ErrJSONUnmarshal, err)
 	}
 
 	return result, nil
 }
+
+// exchange envía una solicitud al SRI y devuelve el estado y el cuerpo de la respuesta.
+// Las solicitudes idempotentes se reintentan según la política configurada.
+func (s *SRIOnline) exchange(ctx context.Context, op, method, url, contentType string, body []byte, idempotent bool) (int, []byte, error) {
+	attempts := 1
+	if idempotent && s.retry.Attempts > 1 {
+		attempts = s.retry.Attempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		status, data, err := s.guarded(ctx, op, method, url, contentType, body)
+		if attempt >= attempts || ctx.Err() != nil || !retryable(status, err) {
+			return status, data, err
+		}
+
+		delay := s.retry.delay(attempt)
+		s.logger.Debug("retrying SRI request", "op", op, "url", url, "attempt", attempt, "status", status, "delay", delay)
+		event := RetryEvent{Op: op, Method: method, URL: url, Attempt: attempt, StatusCode: status, Err: err, Delay: delay}
+		s.hooks.retry(event)
+		if s.instrumentation != nil {
+			s.instrumentation.Retry(ctx, event)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			if err == nil {
+				err = s.fail(op, url, status, data, ErrHTTPStatus, ctx.Err())
+			}
+			return status, data, err
+		case <-timer.C:
+		}
+	}
+}
+
+// guarded envía la solicitud una vez a través del circuito configurado, que la rechaza
+// con ErrCircuitOpen si el servicio no está disponible.
+func (s *SRIOnline) guarded(ctx context.Context, op, method, url, contentType string, body []byte) (int, []byte, error) {
+	if s.breaker == nil {
+		return s.attempt(ctx, op, method, url, contentType, body)
+	}
+
+	service := s.service(url)
+	allowed, trial := s.breaker.allow(service)
+	if !allowed {
+		return 0, nil, s.fail(op, url, 0, nil, ErrCircuitOpen, nil)
+	}
+
+	status, data, err := s.attempt(ctx, op, method, url, contentType, body)
+	s.breaker.record(ctx, service, trial, status, err)
+
+	return status, data, err
+}
+
+// attempt envía la solicitud una vez, respetando el limitador de tasa y el tiempo máximo.
+func (s *SRIOnline) attempt(ctx context.Context, op, method, url, contentType string, body []byte) (int, []byte, error) {
+	if s.limiter != nil {
+		wait, err := s.limiter.Wait(ctx)
+		if err != nil {
+			return 0, nil, s.fail(op, url, 0, nil, ErrHTTPRequest, err)
+		}
+
+		if wait > 0 {
+			s.logger.Debug("SRI request throttled", "op", op, "url", url, "wait", wait)
+			s.hooks.throttle(ThrottleEvent{Op: op, Method: method, URL: url, Wait: wait})
+		}
+	}
+
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+
+	req, err := s.newRequest(ctx, method, url, reader)
+	if err != nil {
+		return 0, nil, s.fail(op, url, 0, nil, ErrHTTPRequest, err)
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil && !errors.Is(err, ctxErr) {
+			err = errors.Join(err, ctxErr)
+		}
+
+		return 0, nil, s.fail(op, url, 0, nil, ErrHTTPRequest, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, s.fail(op, url, resp.StatusCode, nil, ErrReadBody, err)
+	}
+
+	s.logger.Debug("SRI response", "op", op, "method", method, "url", url, "status", resp.StatusCode)
+
+	return resp.StatusCode, data, nil
+}
+
+// fail registra y devuelve un *Error.
+func (s *SRIOnline) fail(op, url string, status int, body []byte, kind, cause error) *Error {
+	err := newError(op, url, status, body, kind, cause)
+
+	attrs := []any{"op", op, "url", url, "error", err}
+	if status != 0 {
+		attrs = append(attrs, "status", status)
+	}
+
+	s.logger.Log(context.Background(), slog.LevelWarn, "SRI request failed", attrs...)
+
+	return err
+}
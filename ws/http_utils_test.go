@@ -1,6 +1,8 @@
 package ws
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -40,27 +42,35 @@ func init() {
 }
 
 func TestGet_Success(t *testing.T) {
-	client := server.Client()
+	client := NewSRIOnline(WithHTTPClient(server.Client()))
 	expected := DummyData{Message: "Hello World!"}
 
-	result, err := get[DummyData](client, testBaseURL+"/success")
+	result, err := get[DummyData](context.Background(), client, "Test", testBaseURL+"/success")
 
 	require.NoError(t, err)
 	require.Equal(t, expected, result)
 }
 
 func TestGet_InvalidJSON(t *testing.T) {
-	client := server.Client()
+	client := NewSRIOnline(WithHTTPClient(server.Client()))
 
-	_, err := get[DummyData](client, testBaseURL+"/invalid-json")
+	_, err := get[DummyData](context.Background(), client, "Test", testBaseURL+"/invalid-json")
 
-	require.Error(t, err)
+	require.ErrorIs(t, err, ErrJSONUnmarshal)
+
+	var syntaxErr *json.SyntaxError
+	require.ErrorAs(t, err, &syntaxErr)
 }
 
 func TestGet_Non200Status(t *testing.T) {
-	client := server.Client()
+	client := NewSRIOnline(WithHTTPClient(server.Client()))
+
+	_, err := get[DummyData](context.Background(), client, "Test", testBaseURL+"/error")
 
-	_, err := get[DummyData](client, testBaseURL+"/error")
+	require.ErrorIs(t, err, ErrHTTPStatus)
 
-	require.Error(t, err)
+	var httpErr *Error
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusInternalServerError, httpErr.StatusCode)
+	require.Equal(t, testBaseURL+"/error", httpErr.URL)
 }
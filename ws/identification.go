@@ -0,0 +1,92 @@
+package ws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pinzlab/sricore/id"
+	"github.com/pinzlab/sricore/sri"
+)
+
+// naturalRUCSuffix es el número de establecimiento con el que se deriva el RUC de una
+// persona natural a partir de su cédula.
+const naturalRUCSuffix = "001"
+
+// Identification contiene los datos de un comprador consultado por su cédula o RUC.
+type Identification struct {
+	// Identification: Cédula o RUC consultado.
+	Identification string
+
+	// RUC: RUC consultado; para una cédula, el RUC de persona natural derivado.
+	RUC string
+
+	// HasRUC: Indica si el RUC está registrado en el SRI.
+	HasRUC bool
+
+	// Name: Razón social o nombres del contribuyente.
+	Name string
+
+	// Status: Estado del RUC.
+	Status ContributorStatus
+
+	// MustKeepAccounting: Indica si el contribuyente está obligado a llevar contabilidad.
+	MustKeepAccounting sri.Bool
+
+	// WithholdingAgent: Indica si el contribuyente actúa como agente de retención.
+	WithholdingAgent sri.Bool
+
+	// SpecialTaxpayer: Indica si el contribuyente es contribuyente especial.
+	SpecialTaxpayer sri.Bool
+
+	// Contributor: Información completa del contribuyente, o nil si no tiene RUC.
+	Contributor *Contributor
+}
+
+// LookupByIdentification consulta un comprador por su cédula o RUC.
+//
+// Una cédula se valida con id.IsDNI y se consulta el RUC de persona natural derivado
+// (cédula + "001"). Si la persona no tiene RUC no se devuelve un error: el resultado
+// tiene HasRUC en false y solo contiene la identificación.
+func (s *SRIOnline) LookupByIdentification(value string) (*Identification, error) {
+	return s.LookupByIdentificationContext(context.Background(), value)
+}
+
+// LookupByIdentificationContext es como LookupByIdentification, pero respeta la
+// cancelación y el plazo del contexto.
+func (s *SRIOnline) LookupByIdentificationContext(ctx context.Context, value string) (*Identification, error) {
+	value = strings.TrimSpace(value)
+	result := &Identification{Identification: value, RUC: value}
+
+	if len(value) == 13 {
+		if err := id.IsRUC(value); err != nil {
+			return nil, err
+		}
+	} else {
+		if err := id.IsDNI(value); err != nil {
+			return nil, err
+		}
+		result.RUC = value + naturalRUCSuffix
+	}
+
+	contributors, err := s.GetContributorsContext(ctx, result.RUC)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, contributor := range contributors {
+		if contributor == nil || contributor.Ruc != result.RUC {
+			continue
+		}
+
+		result.HasRUC = true
+		result.Name = contributor.BusinessName
+		result.Status = contributor.Status
+		result.MustKeepAccounting = contributor.MustKeepAccounting
+		result.WithholdingAgent = contributor.WithholdingAgent
+		result.SpecialTaxpayer = contributor.SpecialTaxpayer
+		result.Contributor = contributor
+		break
+	}
+
+	return result, nil
+}
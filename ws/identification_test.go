@@ -0,0 +1,49 @@
+package ws_test
+
+import (
+	"testing"
+
+	"github.com/pinzlab/sricore/id"
+	"github.com/pinzlab/sricore/ws"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupByIdentification(t *testing.T) {
+	service, server := newTestService(t)
+	server.AddContributor(&ws.Contributor{
+		Ruc:                "0601234560001",
+		BusinessName:       "PEREZ JUAN",
+		Status:             ws.ContributorActive,
+		Type:               ws.NaturalPerson,
+		MustKeepAccounting: true,
+	})
+
+	result, err := service.LookupByIdentification("0601234560")
+	require.NoError(t, err)
+	assert.Equal(t, "0601234560001", result.RUC)
+	assert.True(t, result.HasRUC)
+	assert.Equal(t, "PEREZ JUAN", result.Name)
+	assert.Equal(t, ws.ContributorActive, result.Status)
+	assert.True(t, bool(result.MustKeepAccounting))
+
+	result, err = service.LookupByIdentification(eersaRuc)
+	require.NoError(t, err)
+	assert.Equal(t, eersaRuc, result.RUC)
+	assert.True(t, result.HasRUC)
+}
+
+func TestLookupByIdentification_NoRUC(t *testing.T) {
+	service, _ := newTestService(t)
+
+	result, err := service.LookupByIdentification("1710034065")
+	require.NoError(t, err)
+	assert.Equal(t, "1710034065", result.Identification)
+	assert.Equal(t, "1710034065001", result.RUC)
+	assert.False(t, result.HasRUC)
+	assert.Nil(t, result.Contributor)
+
+	_, err = service.LookupByIdentification("0601234561")
+	assert.Equal(t, id.IsDNI("0601234561"), err)
+	assert.Error(t, err)
+}
@@ -0,0 +1,316 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrorClass clasifica el resultado de una llamada al SRI para métricas y trazas.
+type ErrorClass string
+
+const (
+	// ErrorClassNone indica que la llamada fue exitosa.
+	ErrorClassNone ErrorClass = ""
+
+	// ErrorClassTimeout indica que se agotó el tiempo máximo de la llamada.
+	ErrorClassTimeout ErrorClass = "timeout"
+
+	// ErrorClassCanceled indica que el contexto de la llamada fue cancelado.
+	ErrorClassCanceled ErrorClass = "canceled"
+
+	// ErrorClassNetwork indica un error de conexión o de lectura de la respuesta.
+	ErrorClassNetwork ErrorClass = "network"
+
+	// ErrorClassCircuitOpen indica que el circuito rechazó la llamada porque el servicio
+	// no está disponible.
+	ErrorClassCircuitOpen ErrorClass = "circuit_open"
+
+	// ErrorClassHTTP indica que el SRI respondió con un estado no exitoso.
+	ErrorClassHTTP ErrorClass = "http"
+
+	// ErrorClassSOAPFault indica que el servicio web respondió con un SOAP Fault.
+	ErrorClassSOAPFault ErrorClass = "soap_fault"
+
+	// ErrorClassDecode indica que la respuesta no pudo deserializarse.
+	ErrorClassDecode ErrorClass = "decode"
+
+	// ErrorClassOther indica cualquier otro error.
+	ErrorClassOther ErrorClass = "other"
+)
+
+// ClassifyError devuelve la clase de un error devuelto por el cliente.
+func ClassifyError(err error) ErrorClass {
+	switch {
+	case err == nil:
+		return ErrorClassNone
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorClassCanceled
+	case errors.Is(err, ErrCircuitOpen):
+		return ErrorClassCircuitOpen
+	case errors.Is(err, ErrSOAPFault):
+		return ErrorClassSOAPFault
+	case errors.Is(err, ErrJSONUnmarshal), errors.Is(err, ErrXMLUnmarshal):
+		return ErrorClassDecode
+	case errors.Is(err, ErrHTTPStatus):
+		return ErrorClassHTTP
+	case errors.Is(err, ErrHTTPRequest), errors.Is(err, ErrReadBody):
+		return ErrorClassNetwork
+	default:
+		return ErrorClassOther
+	}
+}
+
+// CallEvent describe una llamada terminada a un servicio del SRI, incluidos sus reintentos.
+type CallEvent struct {
+	// Op: Operación del cliente (por ejemplo, "GetContributors" o "ValidateVoucher").
+	Op string
+
+	// Method: Método HTTP de la solicitud.
+	Method string
+
+	// URL: URL de la solicitud.
+	URL string
+
+	// Duration: Duración total de la llamada, incluidas las esperas entre reintentos.
+	Duration time.Duration
+
+	// StatusCode: Estado HTTP de la última respuesta, o 0 si no hubo respuesta.
+	StatusCode int
+
+	// Class: Clase del error, o ErrorClassNone si la llamada fue exitosa.
+	Class ErrorClass
+
+	// Err: Error devuelto por la llamada (si aplica).
+	Err error
+}
+
+// Instrumentation recibe las llamadas a los servicios del SRI para registrar métricas y
+// trazas (por ejemplo, con Prometheus u OpenTelemetry) sin que el cliente dependa de
+// ellos. Sus métodos pueden invocarse desde varias goroutines a la vez.
+type Instrumentation interface {
+	// Start se invoca al comenzar una llamada. El contexto devuelto se usa en la
+	// solicitud y en los demás métodos, por lo que puede transportar un span.
+	Start(ctx context.Context, op string) context.Context
+
+	// Retry se invoca antes de esperar cada reintento de la llamada.
+	Retry(ctx context.Context, event RetryEvent)
+
+	// End se invoca al terminar la llamada.
+	End(ctx context.Context, event CallEvent)
+}
+
+// WithInstrumentation registra una instrumentación para todas las llamadas al SRI:
+// catastro, matriculación vehicular, recepción y autorización. Las respuestas obtenidas
+// de la caché no se registran.
+func WithInstrumentation(instrumentation Instrumentation) Option {
+	return func(s *SRIOnline) {
+		s.instrumentation = instrumentation
+	}
+}
+
+// observe ejecuta una llamada al SRI notificando su inicio y su fin a la instrumentación.
+func (s *SRIOnline) observe(ctx context.Context, op, method, url string, call func(ctx context.Context) error) error {
+	if s.instrumentation == nil {
+		return call(ctx)
+	}
+
+	start := time.Now()
+	ctx = s.instrumentation.Start(ctx, op)
+
+	err := call(ctx)
+
+	event := CallEvent{Op: op, Method: method, URL: url, Duration: time.Since(start), Class: ClassifyError(err), Err: err}
+	var sriErr *Error
+	if err == nil {
+		event.StatusCode = http.StatusOK
+	} else if errors.As(err, &sriErr) {
+		event.StatusCode = sriErr.StatusCode
+	}
+
+	s.instrumentation.End(ctx, event)
+
+	return err
+}
+
+// OperationStats son las métricas acumuladas de una operación.
+type OperationStats struct {
+	// Calls: Número de llamadas.
+	Calls int
+
+	// Errors: Número de llamadas fallidas.
+	Errors int
+
+	// Retries: Número de reintentos.
+	Retries int
+
+	// TotalDuration: Suma de la duración de las llamadas.
+	TotalDuration time.Duration
+
+	// MaxDuration: Duración de la llamada más lenta.
+	MaxDuration time.Duration
+
+	// Classes: Número de llamadas fallidas por clase de error.
+	Classes map[ErrorClass]int
+
+	// StatusCodes: Número de llamadas por estado HTTP.
+	StatusCodes map[int]int
+}
+
+// ErrorRate devuelve la fracción de llamadas fallidas.
+func (o OperationStats) ErrorRate() float64 {
+	if o.Calls == 0 {
+		return 0
+	}
+
+	return float64(o.Errors) / float64(o.Calls)
+}
+
+// MeanDuration devuelve la duración promedio de las llamadas.
+func (o OperationStats) MeanDuration() time.Duration {
+	if o.Calls == 0 {
+		return 0
+	}
+
+	return o.TotalDuration / time.Duration(o.Calls)
+}
+
+// DefaultMemoryCalls es el número de llamadas que conserva MemoryInstrumentation si no se
+// indica otro.
+const DefaultMemoryCalls = 1000
+
+// MemoryInstrumentation es una Instrumentation que acumula las métricas y las llamadas
+// en memoria, útil en pruebas o para exponerlas en un endpoint de diagnóstico. Las
+// métricas por operación ocupan un espacio fijo; de las llamadas se conservan solo las
+// más recientes.
+type MemoryInstrumentation struct {
+	mu    sync.Mutex
+	stats map[string]*OperationStats
+
+	// calls es un búfer circular con las últimas llamadas; next es la posición de la
+	// siguiente llamada una vez lleno.
+	calls    []CallEvent
+	next     int
+	maxCalls int
+}
+
+var _ Instrumentation = (*MemoryInstrumentation)(nil)
+
+// NewMemoryInstrumentation crea una instrumentación en memoria vacía que conserva las
+// últimas maxCalls llamadas; un valor menor o igual a cero usa DefaultMemoryCalls.
+func NewMemoryInstrumentation(maxCalls int) *MemoryInstrumentation {
+	if maxCalls <= 0 {
+		maxCalls = DefaultMemoryCalls
+	}
+
+	return &MemoryInstrumentation{stats: map[string]*OperationStats{}, maxCalls: maxCalls}
+}
+
+// Start implementa Instrumentation.
+func (m *MemoryInstrumentation) Start(ctx context.Context, op string) context.Context {
+	return ctx
+}
+
+// Retry implementa Instrumentation.
+func (m *MemoryInstrumentation) Retry(ctx context.Context, event RetryEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.operation(event.Op).Retries++
+}
+
+// End implementa Instrumentation.
+func (m *MemoryInstrumentation) End(ctx context.Context, event CallEvent) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := m.operation(event.Op)
+	stats.Calls++
+	stats.TotalDuration += event.Duration
+	stats.MaxDuration = max(stats.MaxDuration, event.Duration)
+	stats.StatusCodes[event.StatusCode]++
+
+	if event.Class != ErrorClassNone {
+		stats.Errors++
+		stats.Classes[event.Class]++
+	}
+
+	if len(m.calls) < m.maxCalls {
+		m.calls = append(m.calls, event)
+		return
+	}
+
+	m.calls[m.next] = event
+	m.next = (m.next + 1) % len(m.calls)
+}
+
+// operation devuelve las métricas de una operación, creándolas si no existen.
+func (m *MemoryInstrumentation) operation(op string) *OperationStats {
+	stats, ok := m.stats[op]
+	if !ok {
+		stats = &OperationStats{Classes: map[ErrorClass]int{}, StatusCodes: map[int]int{}}
+		m.stats[op] = stats
+	}
+
+	return stats
+}
+
+// Stats devuelve una copia de las métricas de una operación.
+func (m *MemoryInstrumentation) Stats(op string) OperationStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.stats[op]
+	if !ok {
+		return OperationStats{Classes: map[ErrorClass]int{}, StatusCodes: map[int]int{}}
+	}
+
+	result := *stats
+	result.Classes = make(map[ErrorClass]int, len(stats.Classes))
+	for class, n := range stats.Classes {
+		result.Classes[class] = n
+	}
+	result.StatusCodes = make(map[int]int, len(stats.StatusCodes))
+	for status, n := range stats.StatusCodes {
+		result.StatusCodes[status] = n
+	}
+
+	return result
+}
+
+// Operations devuelve las operaciones registradas, ordenadas por nombre.
+func (m *MemoryInstrumentation) Operations() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ops := make([]string, 0, len(m.stats))
+	for op := range m.stats {
+		ops = append(ops, op)
+	}
+	slices.Sort(ops)
+
+	return ops
+}
+
+// Calls devuelve las últimas llamadas registradas, de la más antigua a la más reciente.
+func (m *MemoryInstrumentation) Calls() []CallEvent {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append(slices.Clone(m.calls[m.next:]), m.calls[:m.next]...)
+}
+
+// Reset descarta las métricas y llamadas registradas.
+func (m *MemoryInstrumentation) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.stats = map[string]*OperationStats{}
+	m.calls = nil
+	m.next = 0
+}
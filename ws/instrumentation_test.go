@@ -0,0 +1,179 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spanKey es la clave de contexto usada por spanInstrumentation.
+type spanKey struct{}
+
+// spanInstrumentation registra el span recibido en cada evento, para comprobar que el
+// contexto devuelto por Start se propaga.
+type spanInstrumentation struct {
+	*MemoryInstrumentation
+	spans []string
+}
+
+func (s *spanInstrumentation) Start(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, spanKey{}, op)
+}
+
+func (s *spanInstrumentation) Retry(ctx context.Context, event RetryEvent) {
+	s.spans = append(s.spans, fmt.Sprintf("retry:%v", ctx.Value(spanKey{})))
+	s.MemoryInstrumentation.Retry(ctx, event)
+}
+
+func (s *spanInstrumentation) End(ctx context.Context, event CallEvent) {
+	s.spans = append(s.spans, fmt.Sprintf("end:%v", ctx.Value(spanKey{})))
+	s.MemoryInstrumentation.End(ctx, event)
+}
+
+func TestClassifyError(t *testing.T) {
+	cases := map[ErrorClass]error{
+		ErrorClassNone:      nil,
+		ErrorClassTimeout:   newError("op", "url", 0, nil, ErrHTTPRequest, context.DeadlineExceeded),
+		ErrorClassCanceled:  newError("op", "url", 0, nil, ErrHTTPRequest, context.Canceled),
+		ErrorClassNetwork:   newError("op", "url", 0, nil, ErrHTTPRequest, nil),
+		ErrorClassHTTP:      newError("op", "url", 503, nil, ErrHTTPStatus, nil),
+		ErrorClassSOAPFault: newError("op", "url", 500, nil, ErrSOAPFault, nil),
+		ErrorClassDecode:    newError("op", "url", 200, nil, ErrJSONUnmarshal, nil),
+		ErrorClassOther:     ErrInvalidEnv,
+
+		ErrorClassCircuitOpen: newError("op", "url", 0, nil, ErrCircuitOpen, nil),
+	}
+
+	for class, err := range cases {
+		assert.Equal(t, class, ClassifyError(err), "%v", err)
+	}
+}
+
+func TestInstrumentation_Retries(t *testing.T) {
+	server, _ := newFlakyServer(2, `true`)
+	defer server.Close()
+
+	inst := &spanInstrumentation{MemoryInstrumentation: NewMemoryInstrumentation(0)}
+	service := NewSRIOnline(WithBaseURL(server.URL), WithRetry(testRetryPolicy), WithInstrumentation(inst))
+
+	_, err := service.CheckRUC("0690000512001")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"retry:CheckRUC", "retry:CheckRUC", "end:CheckRUC"}, inst.spans)
+
+	stats := inst.Stats("CheckRUC")
+	assert.Equal(t, 1, stats.Calls)
+	assert.Equal(t, 0, stats.Errors)
+	assert.Equal(t, 2, stats.Retries)
+	assert.Equal(t, map[int]int{http.StatusOK: 1}, stats.StatusCodes)
+
+	calls := inst.Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, http.MethodGet, calls[0].Method)
+	assert.Contains(t, calls[0].URL, "0690000512001")
+	assert.Equal(t, ErrorClassNone, calls[0].Class)
+	assert.Positive(t, calls[0].Duration)
+}
+
+func TestInstrumentation_Errors(t *testing.T) {
+	server, _ := newFlakyServer(10, `true`)
+	defer server.Close()
+
+	inst := NewMemoryInstrumentation(0)
+	service := NewSRIOnline(WithBaseURL(server.URL), WithInstrumentation(inst))
+
+	_, err := service.GetContributors("0690000512001")
+	require.Error(t, err)
+	_, err = service.GetEstablishments("0690000512001")
+	require.Error(t, err)
+
+	assert.Equal(t, []string{"GetContributors", "GetEstablishments"}, inst.Operations())
+
+	stats := inst.Stats("GetContributors")
+	assert.Equal(t, 1, stats.Errors)
+	assert.Equal(t, 1.0, stats.ErrorRate())
+	assert.Equal(t, map[ErrorClass]int{ErrorClassHTTP: 1}, stats.Classes)
+	assert.Equal(t, map[int]int{http.StatusServiceUnavailable: 1}, stats.StatusCodes)
+
+	inst.Reset()
+	assert.Empty(t, inst.Operations())
+	assert.Empty(t, inst.Calls())
+	assert.Zero(t, inst.Stats("GetContributors").Calls)
+}
+
+func TestInstrumentation_Decode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{`))
+	}))
+	defer server.Close()
+
+	inst := NewMemoryInstrumentation(0)
+	service := NewSRIOnline(WithBaseURL(server.URL), WithInstrumentation(inst))
+
+	_, err := service.CheckRUC("0690000512001")
+	require.Error(t, err)
+
+	calls := inst.Calls()
+	require.Len(t, calls, 1)
+	assert.Equal(t, ErrorClassDecode, calls[0].Class)
+	assert.Equal(t, http.StatusOK, calls[0].StatusCode)
+	assert.ErrorIs(t, calls[0].Err, ErrJSONUnmarshal)
+}
+
+func TestInstrumentation_Cache(t *testing.T) {
+	server, requests := newFlakyServer(0, `true`)
+	defer server.Close()
+
+	inst := NewMemoryInstrumentation(0)
+	service := NewSRIOnline(
+		WithBaseURL(server.URL),
+		WithCache(NewMemoryCache(), time.Minute, time.Minute),
+		WithInstrumentation(inst),
+	)
+
+	for range 3 {
+		_, err := service.CheckRUC("0690000512001")
+		require.NoError(t, err)
+	}
+
+	// Only the request that reached the SRI is recorded.
+	assert.Equal(t, int32(1), requests.Load())
+	assert.Equal(t, 1, inst.Stats("CheckRUC").Calls)
+}
+
+func TestMemoryInstrumentation_MaxCalls(t *testing.T) {
+	inst := NewMemoryInstrumentation(3)
+
+	for i := range 5 {
+		inst.End(context.Background(), CallEvent{Op: "CheckRUC", StatusCode: 200 + i})
+	}
+
+	// Only the most recent calls are kept, oldest first; the stats count every call.
+	var statuses []int
+	for _, call := range inst.Calls() {
+		statuses = append(statuses, call.StatusCode)
+	}
+	assert.Equal(t, []int{202, 203, 204}, statuses)
+	assert.Equal(t, 5, inst.Stats("CheckRUC").Calls)
+
+	inst.Reset()
+	inst.End(context.Background(), CallEvent{Op: "CheckRUC", StatusCode: 205})
+	require.Len(t, inst.Calls(), 1)
+	assert.Equal(t, 205, inst.Calls()[0].StatusCode)
+}
+
+func TestOperationStats(t *testing.T) {
+	var stats OperationStats
+	assert.Zero(t, stats.ErrorRate())
+	assert.Zero(t, stats.MeanDuration())
+
+	stats = OperationStats{Calls: 4, Errors: 1, TotalDuration: 8 * time.Second}
+	assert.Equal(t, 0.25, stats.ErrorRate())
+	assert.Equal(t, 2*time.Second, stats.MeanDuration())
+}
@@ -0,0 +1,116 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SourceSRIOnline es el nombre de los servicios en línea del SRI como origen de datos.
+const SourceSRIOnline = "sri"
+
+// ContributorLookup consulta el catastro de contribuyentes. *SRIOnline la implementa con
+// los servicios en línea del SRI; otras implementaciones pueden usar datos locales.
+type ContributorLookup interface {
+	CheckRUCContext(ctx context.Context, ruc string) (bool, error)
+	GetContributorsContext(ctx context.Context, ruc string) ([]*Contributor, error)
+	GetEstablishmentsContext(ctx context.Context, ruc string) ([]*Establishment, error)
+}
+
+// DataSource describe el catastro que respondió una consulta y la fecha de sus datos.
+type DataSource struct {
+	// Name: Nombre del catastro, por ejemplo SourceSRIOnline o el de un índice local.
+	Name string
+
+	// AsOf: Fecha de los datos: la fecha en que se obtuvieron del SRI (también para las
+	// respuestas almacenadas en caché) o la de la última importación de un índice local.
+	AsOf time.Time
+}
+
+// DatedLookup es un ContributorLookup que informa el origen y la fecha de los datos de
+// cada consulta.
+type DatedLookup interface {
+	ContributorLookup
+
+	// GetContributorsSource es como GetContributorsContext, pero también devuelve el
+	// origen de los datos.
+	GetContributorsSource(ctx context.Context, ruc string) ([]*Contributor, DataSource, error)
+}
+
+var (
+	_ DatedLookup = (*SRIOnline)(nil)
+	_ DatedLookup = (*FallbackLookup)(nil)
+)
+
+// FallbackLookup consulta primero un catastro principal y, si no está disponible, uno
+// secundario (por ejemplo, un índice local cuando el SRI no responde).
+type FallbackLookup struct {
+	primary   ContributorLookup
+	secondary ContributorLookup
+}
+
+// NewFallbackLookup crea un catastro que usa secondary cuando primary falla por errores
+// de comunicación (ErrHTTPRequest, ErrHTTPStatus o ErrReadBody).
+func NewFallbackLookup(primary, secondary ContributorLookup) *FallbackLookup {
+	return &FallbackLookup{primary: primary, secondary: secondary}
+}
+
+// CheckRUCContext implementa ContributorLookup.
+func (f *FallbackLookup) CheckRUCContext(ctx context.Context, ruc string) (bool, error) {
+	return fallback(ctx, ruc, f.primary.CheckRUCContext, f.secondary.CheckRUCContext)
+}
+
+// GetContributorsContext implementa ContributorLookup.
+func (f *FallbackLookup) GetContributorsContext(ctx context.Context, ruc string) ([]*Contributor, error) {
+	return fallback(ctx, ruc, f.primary.GetContributorsContext, f.secondary.GetContributorsContext)
+}
+
+// GetContributorsSource implementa DatedLookup. El origen es el del catastro que
+// respondió: el principal o, si no estuvo disponible, el secundario.
+func (f *FallbackLookup) GetContributorsSource(ctx context.Context, ruc string) ([]*Contributor, DataSource, error) {
+	contributors, source, err := contributorsSource(ctx, f.primary, ruc)
+	if err == nil || ctx.Err() != nil || !unavailable(err) {
+		return contributors, source, err
+	}
+
+	return contributorsSource(ctx, f.secondary, ruc)
+}
+
+// GetEstablishmentsContext implementa ContributorLookup.
+func (f *FallbackLookup) GetEstablishmentsContext(ctx context.Context, ruc string) ([]*Establishment, error) {
+	return fallback(ctx, ruc, f.primary.GetEstablishmentsContext, f.secondary.GetEstablishmentsContext)
+}
+
+// fallback invoca primary y, ante un error de comunicación, secondary.
+func fallback[T any](ctx context.Context, ruc string, primary, secondary func(context.Context, string) (T, error)) (T, error) {
+	result, err := primary(ctx, ruc)
+	if err == nil || ctx.Err() != nil || !unavailable(err) {
+		return result, err
+	}
+
+	return secondary(ctx, ruc)
+}
+
+// unavailable indica si el error se debe a que el servicio no está disponible.
+func unavailable(err error) bool {
+	return errors.Is(err, ErrHTTPRequest) || errors.Is(err, ErrHTTPStatus) || errors.Is(err, ErrReadBody) ||
+		errors.Is(err, ErrCircuitOpen)
+}
+
+// contributorsSource consulta los contribuyentes y el origen de los datos. Para los
+// catastros que no implementan DatedLookup, la fecha de los datos es la de su método
+// UpdatedAt, si lo tienen, o la de la consulta.
+func contributorsSource(ctx context.Context, lookup ContributorLookup, ruc string) ([]*Contributor, DataSource, error) {
+	if dated, ok := lookup.(DatedLookup); ok {
+		return dated.GetContributorsSource(ctx, ruc)
+	}
+
+	contributors, err := lookup.GetContributorsContext(ctx, ruc)
+
+	source := DataSource{AsOf: time.Now()}
+	if updated, ok := lookup.(interface{ UpdatedAt() time.Time }); ok {
+		source.AsOf = updated.UpdatedAt()
+	}
+
+	return contributors, source, err
+}
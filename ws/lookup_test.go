@@ -0,0 +1,67 @@
+package ws_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pinzlab/sricore/ws"
+)
+
+// staticLookup es un catastro fijo usado como respaldo en las pruebas.
+type staticLookup struct {
+	contributors   map[string]*ws.Contributor
+	establishments map[string][]*ws.Establishment
+	calls          int
+}
+
+func (l *staticLookup) CheckRUCContext(ctx context.Context, ruc string) (bool, error) {
+	l.calls++
+	_, ok := l.contributors[ruc]
+	return ok, nil
+}
+
+func (l *staticLookup) GetContributorsContext(ctx context.Context, ruc string) ([]*ws.Contributor, error) {
+	l.calls++
+	if c, ok := l.contributors[ruc]; ok {
+		return []*ws.Contributor{c}, nil
+	}
+	return []*ws.Contributor{}, nil
+}
+
+func (l *staticLookup) GetEstablishmentsContext(ctx context.Context, ruc string) ([]*ws.Establishment, error) {
+	l.calls++
+	if e, ok := l.establishments[ruc]; ok {
+		return e, nil
+	}
+	return []*ws.Establishment{}, nil
+}
+
+func TestFallbackLookup(t *testing.T) {
+	service, server := newTestService(t)
+	secondary := &staticLookup{contributors: map[string]*ws.Contributor{
+		eersaRuc: {Ruc: eersaRuc, BusinessName: "EERSA LOCAL"},
+	}}
+	lookup := ws.NewFallbackLookup(service, secondary)
+	ctx := context.Background()
+
+	contributors, err := lookup.GetContributorsContext(ctx, eersaRuc)
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+	assert.Equal(t, "EMPRESA ELECTRICA RIOBAMBA SA", contributors[0].BusinessName)
+	assert.Equal(t, 0, secondary.calls)
+
+	server.Outage(-1)
+
+	contributors, err = lookup.GetContributorsContext(ctx, eersaRuc)
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+	assert.Equal(t, "EERSA LOCAL", contributors[0].BusinessName)
+
+	ok, err := lookup.CheckRUCContext(ctx, eersaRuc)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, secondary.calls)
+}
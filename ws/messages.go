@@ -0,0 +1,217 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind clasifica cómo debe reaccionar el emisor ante un mensaje de error del SRI.
+type ErrorKind int
+
+const (
+	// KindPermanent indica que el comprobante debe corregirse antes de volver a enviarse.
+	KindPermanent ErrorKind = iota
+
+	// KindRetryable indica un problema transitorio; el mismo comprobante puede reenviarse
+	// o consultarse más tarde.
+	KindRetryable
+
+	// KindAlreadyDone indica que el SRI ya recibió el comprobante; solo resta consultar
+	// su autorización.
+	KindAlreadyDone
+)
+
+// String devuelve el nombre de la clasificación.
+func (k ErrorKind) String() string {
+	switch k {
+	case KindRetryable:
+		return "reintentable"
+	case KindAlreadyDone:
+		return "ya procesado"
+	default:
+		return "permanente"
+	}
+}
+
+// VoucherError es un error del catálogo de mensajes de los servicios de recepción y
+// autorización del SRI, identificado por su código numérico.
+type VoucherError struct {
+	// ID: Identificador numérico del mensaje según la ficha técnica del SRI.
+	ID string
+
+	// Message: Mensaje con el que responde el SRI.
+	Message string
+
+	// Kind: Clasificación del error para decidir si reintentar.
+	Kind ErrorKind
+
+	// Hint: Sugerencia para corregir el problema.
+	Hint string
+}
+
+// Error implementa la interfaz error.
+func (e *VoucherError) Error() string {
+	return fmt.Sprintf("%s %s", e.ID, e.Message)
+}
+
+// Is permite comparar errores del catálogo por su identificador.
+func (e *VoucherError) Is(target error) bool {
+	t, ok := target.(*VoucherError)
+	return ok && t.ID == e.ID
+}
+
+// Errores del catálogo de mensajes de recepción y autorización.
+var (
+	ErrTaxpayerInactive     = &VoucherError{ID: "2", Message: "RUC DEL EMISOR SE ENCUENTRA NO ACTIVO", Kind: KindPermanent, Hint: "Verifique el estado del RUC del emisor en el SRI"}
+	ErrEstablishmentClosure = &VoucherError{ID: "10", Message: "ESTABLECIMIENTO DEL EMISOR SE ENCUENTRA CLAUSURADO", Kind: KindPermanent, Hint: "El establecimiento está clausurado; emita desde un establecimiento habilitado"}
+	ErrMaxSizeExceeded      = &VoucherError{ID: "26", Message: "TAMAÑO MÁXIMO SUPERADO", Kind: KindPermanent, Hint: "El comprobante supera el tamaño permitido; reduzca el detalle o la información adicional"}
+	ErrClassNotAllowed      = &VoucherError{ID: "27", Message: "CLASE NO PERMITIDA", Kind: KindPermanent, Hint: "La clase de contribuyente no está autorizada para emitir este tipo de comprobante"}
+	ErrAgreementNotAccepted = &VoucherError{ID: "28", Message: "ACUERDO DE MEDIOS ELECTRÓNICOS NO ACEPTADO", Kind: KindPermanent, Hint: "Acepte el acuerdo de medios electrónicos en SRI en línea"}
+	ErrXMLStructure         = &VoucherError{ID: "35", Message: "ARCHIVO NO CUMPLE ESTRUCTURA XML", Kind: KindPermanent, Hint: "Valide el comprobante contra el esquema XSD de su versión"}
+	ErrNotAuthorizedToIssue = &VoucherError{ID: "37", Message: "RUC SIN AUTORIZACIÓN DE EMISIÓN", Kind: KindPermanent, Hint: "Solicite la autorización de emisión electrónica para el RUC"}
+	ErrInvalidSignature     = &VoucherError{ID: "39", Message: "FIRMA INVALIDA", Kind: KindPermanent, Hint: "Verifique el certificado de firma y vuelva a firmar el comprobante sin modificarlo"}
+	ErrInvalidCertificate   = &VoucherError{ID: "40", Message: "ERROR EN EL CERTIFICADO", Kind: KindPermanent, Hint: "El certificado de firma está caducado, revocado o no pertenece al emisor"}
+	ErrAccessKeyRegistered  = &VoucherError{ID: "43", Message: "CLAVE ACCESO REGISTRADA", Kind: KindAlreadyDone, Hint: "El comprobante ya fue recibido; consulte su autorización"}
+	ErrSequentialRegistered = &VoucherError{ID: "45", Message: "SECUENCIAL REGISTRADO", Kind: KindPermanent, Hint: "El secuencial ya fue usado con otra clave de acceso; consulte el comprobante existente o use el siguiente secuencial"}
+	ErrTaxpayerNotFound     = &VoucherError{ID: "46", Message: "RUC NO EXISTE", Kind: KindPermanent, Hint: "Verifique el RUC del emisor"}
+	ErrVoucherTypeNotFound  = &VoucherError{ID: "47", Message: "TIPO DE COMPROBANTE NO EXISTE", Kind: KindPermanent, Hint: "Verifique el código del tipo de comprobante"}
+	ErrSchemaNotFound       = &VoucherError{ID: "48", Message: "ESQUEMA XSD NO EXISTE", Kind: KindPermanent, Hint: "Verifique la versión del comprobante en el atributo version"}
+	ErrNullArguments        = &VoucherError{ID: "49", Message: "ARGUMENTOS QUE ENVIAN AL WS NULOS", Kind: KindPermanent, Hint: "El comprobante enviado está vacío"}
+	ErrInternalError        = &VoucherError{ID: "50", Message: "ERROR INTERNO GENERAL", Kind: KindRetryable, Hint: "Error interno del SRI; reintente más tarde"}
+	ErrTotalsMismatch       = &VoucherError{ID: "52", Message: "ERROR EN DIFERENCIAS", Kind: KindPermanent, Hint: "Revise los totales, impuestos y descuentos del comprobante"}
+	ErrEstablishmentClosed  = &VoucherError{ID: "56", Message: "ESTABLECIMIENTO CERRADO", Kind: KindPermanent, Hint: "El establecimiento está cerrado en el RUC; emita desde un establecimiento abierto"}
+	ErrAuthorizationPaused  = &VoucherError{ID: "57", Message: "AUTORIZACIÓN SUSPENDIDA", Kind: KindPermanent, Hint: "La autorización de emisión electrónica está suspendida; contacte al SRI"}
+	ErrAccessKeyStructure   = &VoucherError{ID: "58", Message: "ERROR EN LA ESTRUCTURA DE CLAVE ACCESO", Kind: KindPermanent, Hint: "Genere nuevamente la clave de acceso con los datos del comprobante"}
+	ErrTaxpayerClosed       = &VoucherError{ID: "63", Message: "RUC CLAUSURADO", Kind: KindPermanent, Hint: "El RUC del emisor está clausurado"}
+	ErrLateIssueDate        = &VoucherError{ID: "65", Message: "FECHA DE EMISIÓN EXTEMPORANEA", Kind: KindPermanent, Hint: "La fecha de emisión supera el plazo permitido para el envío"}
+	ErrInvalidDate          = &VoucherError{ID: "67", Message: "FECHA INVALIDA", Kind: KindPermanent, Hint: "Verifique el formato dd/mm/aaaa de las fechas del comprobante"}
+	ErrAccessKeyProcessing  = &VoucherError{ID: "70", Message: "CLAVE DE ACCESO EN PROCESAMIENTO", Kind: KindRetryable, Hint: "El comprobante aún se está procesando; consulte su autorización más tarde"}
+)
+
+// voucherErrors indexa el catálogo por identificador.
+var voucherErrors = map[string]*VoucherError{}
+
+func init() {
+	for _, err := range []*VoucherError{
+		ErrTaxpayerInactive, ErrEstablishmentClosure, ErrMaxSizeExceeded, ErrClassNotAllowed,
+		ErrAgreementNotAccepted, ErrXMLStructure, ErrNotAuthorizedToIssue, ErrInvalidSignature,
+		ErrInvalidCertificate, ErrAccessKeyRegistered, ErrSequentialRegistered, ErrTaxpayerNotFound,
+		ErrVoucherTypeNotFound, ErrSchemaNotFound, ErrNullArguments, ErrInternalError,
+		ErrTotalsMismatch, ErrEstablishmentClosed, ErrAuthorizationPaused, ErrAccessKeyStructure,
+		ErrTaxpayerClosed, ErrLateIssueDate, ErrInvalidDate, ErrAccessKeyProcessing,
+	} {
+		voucherErrors[err.ID] = err
+	}
+}
+
+// LookupVoucherError devuelve el error del catálogo para el identificador indicado.
+func LookupVoucherError(id string) (*VoucherError, bool) {
+	err, ok := voucherErrors[id]
+	return err, ok
+}
+
+// Err convierte el mensaje en un error que coincide con el catálogo mediante errors.Is.
+// Los identificadores desconocidos se consideran permanentes.
+func (m *Message) Err() error {
+	err, ok := voucherErrors[m.ID]
+	if !ok {
+		err = &VoucherError{ID: m.ID, Message: m.Message, Kind: KindPermanent}
+	}
+
+	if m.AdditionalInfo == "" {
+		return err
+	}
+
+	return fmt.Errorf("%w: %s", err, m.AdditionalInfo)
+}
+
+// Err devuelve los errores de una recepción DEVUELTA, o nil si fue RECIBIDA.
+// Los mensajes de advertencia o informativos se ignoran.
+func (r *ReceptionResponse) Err() error {
+	if r.Status != ReceptionReturned {
+		return nil
+	}
+
+	var errs []error
+	for _, message := range r.Messages() {
+		if message.Type == MessageError {
+			errs = append(errs, message.Err())
+		}
+	}
+
+	if len(errs) == 0 {
+		return fmt.Errorf("%w: %s", ErrXMLStructure, ReceptionReturned)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Err devuelve los errores de una autorización NO AUTORIZADO, o nil en otro caso.
+func (a *Authorization) Err() error {
+	if a.Status != AuthorizationRejected {
+		return nil
+	}
+
+	var errs []error
+	for _, message := range a.Messages {
+		if message.Type == MessageError {
+			errs = append(errs, message.Err())
+		}
+	}
+
+	if len(errs) == 0 {
+		return &VoucherError{Message: string(AuthorizationRejected), Kind: KindPermanent}
+	}
+
+	return errors.Join(errs...)
+}
+
+// KindOf devuelve la clasificación de un error del catálogo. Si el error agrupa varios
+// mensajes, prevalece el permanente, luego el ya procesado y por último el reintentable.
+// Los errores que no pertenecen al catálogo se consideran permanentes.
+func KindOf(err error) ErrorKind {
+	var kinds []ErrorKind
+	collectKinds(err, &kinds)
+
+	if len(kinds) == 0 {
+		return KindPermanent
+	}
+
+	result := KindRetryable
+	for _, kind := range kinds {
+		switch {
+		case kind == KindPermanent:
+			return KindPermanent
+		case kind == KindAlreadyDone:
+			result = KindAlreadyDone
+		}
+	}
+
+	return result
+}
+
+// collectKinds recorre el árbol de errores y acumula las clasificaciones encontradas.
+func collectKinds(err error, kinds *[]ErrorKind) {
+	switch e := err.(type) {
+	case nil:
+		return
+	case *VoucherError:
+		*kinds = append(*kinds, e.Kind)
+	case interface{ Unwrap() []error }:
+		for _, inner := range e.Unwrap() {
+			collectKinds(inner, kinds)
+		}
+	case interface{ Unwrap() error }:
+		collectKinds(e.Unwrap(), kinds)
+	}
+}
+
+// IsRetryable indica si el error es transitorio y puede reintentarse.
+func IsRetryable(err error) bool {
+	return err != nil && KindOf(err) == KindRetryable
+}
+
+// IsAlreadyDone indica si el error significa que el SRI ya recibió el comprobante.
+func IsAlreadyDone(err error) bool {
+	return err != nil && KindOf(err) == KindAlreadyDone
+}
@@ -0,0 +1,70 @@
+package ws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage_Err(t *testing.T) {
+	message := &Message{ID: "43", Message: "CLAVE ACCESO REGISTRADA", AdditionalInfo: "La clave de acceso ya se encuentra registrada", Type: MessageError}
+
+	err := message.Err()
+	assert.ErrorIs(t, err, ErrAccessKeyRegistered)
+	assert.NotErrorIs(t, err, ErrXMLStructure)
+	assert.True(t, IsAlreadyDone(err))
+
+	var voucherErr *VoucherError
+	require.True(t, errors.As(err, &voucherErr))
+	assert.Equal(t, "El comprobante ya fue recibido; consulte su autorización", voucherErr.Hint)
+
+	unknown := (&Message{ID: "999", Message: "DESCONOCIDO"}).Err()
+	assert.Equal(t, KindPermanent, KindOf(unknown))
+	assert.EqualError(t, unknown, "999 DESCONOCIDO")
+}
+
+func TestLookupVoucherError(t *testing.T) {
+	err, ok := LookupVoucherError("70")
+	require.True(t, ok)
+	assert.Same(t, ErrAccessKeyProcessing, err)
+	assert.True(t, IsRetryable(err))
+
+	_, ok = LookupVoucherError("0")
+	assert.False(t, ok)
+}
+
+func TestKindOf(t *testing.T) {
+	assert.Equal(t, KindRetryable, KindOf(errors.Join(ErrInternalError, ErrAccessKeyProcessing)))
+	assert.Equal(t, KindAlreadyDone, KindOf(errors.Join(ErrInternalError, ErrAccessKeyRegistered)))
+	assert.Equal(t, KindPermanent, KindOf(errors.Join(ErrAccessKeyRegistered, ErrInvalidSignature)))
+	assert.Equal(t, KindPermanent, KindOf(ErrSOAPFault))
+	assert.False(t, IsRetryable(nil))
+}
+
+func TestReceptionResponse_Err(t *testing.T) {
+	received := &ReceptionResponse{Status: ReceptionReceived}
+	assert.NoError(t, received.Err())
+
+	returned := &ReceptionResponse{
+		Status: ReceptionReturned,
+		Vouchers: []*ReceivedVoucher{{Messages: []*Message{
+			{ID: "35", Message: "ARCHIVO NO CUMPLE ESTRUCTURA XML", Type: MessageError},
+			{ID: "60", Message: "AMBIENTE DE PRUEBAS", Type: MessageInfo},
+		}}},
+	}
+	err := returned.Err()
+	assert.ErrorIs(t, err, ErrXMLStructure)
+	assert.Equal(t, KindPermanent, KindOf(err))
+}
+
+func TestAuthorization_Err(t *testing.T) {
+	assert.NoError(t, (&Authorization{Status: AuthorizationAuthorized}).Err())
+
+	rejected := &Authorization{
+		Status:   AuthorizationRejected,
+		Messages: []*Message{{ID: "39", Message: "FIRMA INVALIDA", Type: MessageError}},
+	}
+	assert.ErrorIs(t, rejected.Err(), ErrInvalidSignature)
+}
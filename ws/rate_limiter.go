@@ -0,0 +1,81 @@
+package ws
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter es un limitador de tasa de tipo token bucket. Una misma instancia puede
+// compartirse entre varios clientes y goroutines para limitar el total de solicitudes
+// enviadas al SRI.
+type RateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter crea un limitador que permite perSecond solicitudes por segundo, con
+// ráfagas de hasta burst solicitudes. Una tasa de cero o negativa no limita las solicitudes.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &RateLimiter{
+		rate:   perSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait espera un turno para enviar una solicitud y devuelve el tiempo esperado. Si el
+// contexto se cancela antes, el turno se libera y se devuelve el error del contexto.
+func (l *RateLimiter) Wait(ctx context.Context) (time.Duration, error) {
+	wait := l.reserve()
+	if wait <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		l.release()
+		return 0, ctx.Err()
+	case <-timer.C:
+		return wait, nil
+	}
+}
+
+// reserve toma un turno y devuelve cuánto debe esperarse hasta que esté disponible.
+func (l *RateLimiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+
+	l.tokens--
+	if l.tokens >= 0 || l.rate <= 0 {
+		return 0
+	}
+
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// release devuelve un turno reservado que no se usó.
+func (l *RateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.tokens++
+}
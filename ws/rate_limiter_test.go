@@ -0,0 +1,63 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	limiter := NewRateLimiter(50, 2)
+
+	start := time.Now()
+	for range 4 {
+		_, err := limiter.Wait(context.Background())
+		require.NoError(t, err)
+	}
+
+	// Las dos primeras solicitudes usan la ráfaga; las otras esperan 20ms cada una.
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := limiter.Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestRateLimiter_Shared(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`true`))
+	}))
+	defer server.Close()
+
+	var throttled atomic.Int32
+	limiter := NewRateLimiter(100, 1)
+	hooks := Hooks{OnThrottle: func(ThrottleEvent) { throttled.Add(1) }}
+
+	first := NewSRIOnline(WithBaseURL(server.URL), WithRateLimiter(limiter), WithHooks(hooks))
+	second := NewSRIOnline(WithBaseURL(server.URL), WithRateLimiter(limiter), WithHooks(hooks))
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for _, service := range []*SRIOnline{first, second, first, second, first} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := service.CheckRUC("0690000512001")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	assert.GreaterOrEqual(t, time.Since(start), 35*time.Millisecond)
+	assert.Equal(t, int32(4), throttled.Load())
+}
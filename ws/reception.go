@@ -0,0 +1,110 @@
+package ws
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/pinzlab/sricore/sri"
+)
+
+const receptionNamespace = "http://ec.gob.sri.ws.recepcion"
+
+// ReceptionStatus es el estado con el que el SRI responde a la recepción de un comprobante.
+type ReceptionStatus string
+
+const (
+	// ReceptionReceived indica que el comprobante fue recibido y pasa a autorización.
+	ReceptionReceived ReceptionStatus = "RECIBIDA"
+
+	// ReceptionReturned indica que el comprobante fue devuelto por errores.
+	ReceptionReturned ReceptionStatus = "DEVUELTA"
+)
+
+// MessageType es la clasificación de un mensaje devuelto por los servicios web del SRI.
+type MessageType string
+
+const (
+	// MessageError indica un error que impide procesar el comprobante.
+	MessageError MessageType = "ERROR"
+
+	// MessageWarning indica una advertencia que no impide procesar el comprobante.
+	MessageWarning MessageType = "ADVERTENCIA"
+
+	// MessageInfo indica un mensaje informativo.
+	MessageInfo MessageType = "INFORMATIVO"
+)
+
+// Message es un mensaje devuelto por los servicios de recepción y autorización.
+type Message struct {
+	// ID: Identificador numérico del mensaje según el catálogo del SRI.
+	ID string `xml:"identificador"`
+
+	// Message: Descripción del mensaje.
+	Message string `xml:"mensaje"`
+
+	// AdditionalInfo: Información adicional sobre el mensaje (si aplica).
+	AdditionalInfo string `xml:"informacionAdicional"`
+
+	// Type: Tipo del mensaje (ERROR, ADVERTENCIA o INFORMATIVO).
+	Type MessageType `xml:"tipo"`
+}
+
+// ReceivedVoucher contiene el resultado de la recepción de un comprobante.
+type ReceivedVoucher struct {
+	// AccessKey: Clave de acceso del comprobante.
+	AccessKey string `xml:"claveAcceso"`
+
+	// Messages: Mensajes asociados al comprobante.
+	Messages []*Message `xml:"mensajes>mensaje"`
+}
+
+// ReceptionResponse es la respuesta del servicio RecepcionComprobantesOffline.
+type ReceptionResponse struct {
+	// Status: Estado de la recepción (RECIBIDA o DEVUELTA).
+	Status ReceptionStatus `xml:"estado"`
+
+	// Vouchers: Comprobantes procesados con sus mensajes.
+	Vouchers []*ReceivedVoucher `xml:"comprobantes>comprobante"`
+}
+
+// Messages devuelve todos los mensajes de los comprobantes de la respuesta.
+func (r *ReceptionResponse) Messages() []*Message {
+	var messages []*Message
+	for _, voucher := range r.Vouchers {
+		messages = append(messages, voucher.Messages...)
+	}
+
+	return messages
+}
+
+// receptionResult es el contenido del cuerpo SOAP de validarComprobanteResponse.
+type receptionResult struct {
+	Response ReceptionResponse `xml:"RespuestaRecepcionComprobante"`
+}
+
+// ValidateVoucher envía un comprobante firmado al servicio de recepción del SRI
+// (RecepcionComprobantesOffline, operación validarComprobante) en el ambiente indicado.
+//
+// El comprobante se envía codificado en base64. Un estado DEVUELTA no se considera
+// un error: los motivos se encuentran en los mensajes de la respuesta.
+func (s *SRIOnline) ValidateVoucher(env sri.EnvType, signed []byte) (*ReceptionResponse, error) {
+	return s.ValidateVoucherContext(context.Background(), env, signed)
+}
+
+// ValidateVoucherContext es como ValidateVoucher, pero respeta la cancelación y el
+// plazo del contexto.
+func (s *SRIOnline) ValidateVoucherContext(ctx context.Context, env sri.EnvType, signed []byte) (*ReceptionResponse, error) {
+	url, err := s.voucherURL(env, "/RecepcionComprobantesOffline")
+	if err != nil {
+		return nil, err
+	}
+
+	body := soapRequest(receptionNamespace, "validarComprobante", "xml", base64.StdEncoding.EncodeToString(signed))
+
+	result, err := post[receptionResult](ctx, s, "ValidateVoucher", url, body, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &result.Response, nil
+}
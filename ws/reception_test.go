@@ -0,0 +1,126 @@
+package ws
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const returnedResponse = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+<soap:Body>
+<ns2:validarComprobanteResponse xmlns:ns2="http://ec.gob.sri.ws.recepcion">
+<RespuestaRecepcionComprobante>
+<estado>DEVUELTA</estado>
+<comprobantes>
+<comprobante>
+<claveAcceso>2110201101179214673900110020010000000011234567813</claveAcceso>
+<mensajes>
+<mensaje>
+<identificador>43</identificador>
+<mensaje>CLAVE ACCESO REGISTRADA</mensaje>
+<informacionAdicional>La clave de acceso ya se encuentra registrada</informacionAdicional>
+<tipo>ERROR</tipo>
+</mensaje>
+</mensajes>
+</comprobante>
+</comprobantes>
+</RespuestaRecepcionComprobante>
+</ns2:validarComprobanteResponse>
+</soap:Body>
+</soap:Envelope>`
+
+const receivedResponse = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+<soap:Body>
+<ns2:validarComprobanteResponse xmlns:ns2="http://ec.gob.sri.ws.recepcion">
+<RespuestaRecepcionComprobante><estado>RECIBIDA</estado><comprobantes/></RespuestaRecepcionComprobante>
+</ns2:validarComprobanteResponse>
+</soap:Body>
+</soap:Envelope>`
+
+const faultResponse = `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+<soap:Body>
+<soap:Fault><faultcode>soap:Server</faultcode><faultstring>Error interno</faultstring></soap:Fault>
+</soap:Body>
+</soap:Envelope>`
+
+// newReceptionServer crea un servidor que imita RecepcionComprobantesOffline.
+func newReceptionServer(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, sriVouchers+"/RecepcionComprobantesOffline", r.URL.Path)
+
+		body, _ := io.ReadAll(r.Body)
+		request := string(body)
+		assert.Contains(t, request, "<ec:validarComprobante>")
+
+		switch {
+		case strings.Contains(request, base64.StdEncoding.EncodeToString([]byte("<factura/>"))):
+			_, _ = w.Write([]byte(receivedResponse))
+		case strings.Contains(request, base64.StdEncoding.EncodeToString([]byte("<registrada/>"))):
+			_, _ = w.Write([]byte(returnedResponse))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(faultResponse))
+		}
+	}))
+}
+
+func TestValidateVoucher(t *testing.T) {
+	server := newReceptionServer(t)
+	defer server.Close()
+
+	service := NewSRIOnline(WithVouchersURL(sri.EnvTest, server.URL))
+
+	res, err := service.ValidateVoucher(sri.EnvTest, []byte("<factura/>"))
+	require.NoError(t, err)
+	assert.Equal(t, ReceptionReceived, res.Status)
+	assert.Empty(t, res.Messages())
+
+	res, err = service.ValidateVoucher(sri.EnvTest, []byte("<registrada/>"))
+	require.NoError(t, err)
+	assert.Equal(t, ReceptionReturned, res.Status)
+	require.Len(t, res.Vouchers, 1)
+	assert.Equal(t, "2110201101179214673900110020010000000011234567813", res.Vouchers[0].AccessKey)
+	assert.Equal(t, []*Message{{
+		ID:             "43",
+		Message:        "CLAVE ACCESO REGISTRADA",
+		AdditionalInfo: "La clave de acceso ya se encuentra registrada",
+		Type:           MessageError,
+	}}, res.Messages())
+
+	_, err = service.ValidateVoucher(sri.EnvTest, []byte("<error/>"))
+	assert.ErrorIs(t, err, ErrSOAPFault)
+
+	_, err = service.ValidateVoucher(sri.EnvType("9"), []byte("<factura/>"))
+	assert.ErrorIs(t, err, ErrInvalidEnv)
+}
+
+func TestValidateVoucherContext_Canceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+
+	service := NewSRIOnline(WithVouchersURL(sri.EnvTest, server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := service.ValidateVoucherContext(ctx, sri.EnvTest, []byte("<factura/>"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	accessKey, err := testAccessKey.Generate()
+	require.NoError(t, err)
+
+	_, err = service.AuthorizeVoucherContext(ctx, accessKey)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,140 @@
+package ws
+
+import (
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy define los reintentos de las solicitudes idempotentes al SRI (consultas del
+// catastro y de autorización) ante errores de conexión y respuestas 5xx o 429.
+type RetryPolicy struct {
+	// Attempts: Número máximo de intentos, incluido el primero. 0 o 1 desactiva los reintentos.
+	Attempts int
+
+	// Initial: Espera antes del primer reintento.
+	Initial time.Duration
+
+	// Max: Espera máxima entre reintentos.
+	Max time.Duration
+
+	// Multiplier: Factor por el que crece la espera tras cada reintento.
+	Multiplier float64
+
+	// Jitter: Fracción de la espera (entre 0 y 1) que se reduce al azar para evitar que
+	// varios clientes reintenten al mismo tiempo.
+	Jitter float64
+}
+
+// DefaultRetryPolicy es una política recomendada para los servicios del SRI.
+var DefaultRetryPolicy = RetryPolicy{
+	Attempts:   4,
+	Initial:    500 * time.Millisecond,
+	Max:        10 * time.Second,
+	Multiplier: 2,
+	Jitter:     0.5,
+}
+
+// delay devuelve la espera antes del reintento indicado (desde 1).
+func (p RetryPolicy) delay(retry int) time.Duration {
+	delay := float64(p.Initial)
+	for i := 1; i < retry; i++ {
+		delay *= p.Multiplier
+	}
+
+	if p.Max > 0 && delay > float64(p.Max) {
+		delay = float64(p.Max)
+	}
+
+	if p.Jitter > 0 {
+		delay -= delay * p.Jitter * rand.Float64()
+	}
+
+	return time.Duration(delay)
+}
+
+// retryable indica si el resultado de un intento justifica reintentar la solicitud.
+func retryable(status int, err error) bool {
+	if err != nil {
+		return errors.Is(err, ErrHTTPRequest) || errors.Is(err, ErrReadBody)
+	}
+
+	return status >= http.StatusInternalServerError || status == http.StatusTooManyRequests
+}
+
+// RetryEvent describe un reintento de una solicitud al SRI.
+type RetryEvent struct {
+	// Op: Operación del cliente (por ejemplo, "CheckRUC").
+	Op string
+
+	// Method: Método HTTP de la solicitud.
+	Method string
+
+	// URL: URL de la solicitud.
+	URL string
+
+	// Attempt: Número del intento fallido (desde 1).
+	Attempt int
+
+	// StatusCode: Estado HTTP de la respuesta fallida, o 0 si no hubo respuesta.
+	StatusCode int
+
+	// Err: Error del intento fallido, si no hubo respuesta.
+	Err error
+
+	// Delay: Espera antes del siguiente intento.
+	Delay time.Duration
+}
+
+// ThrottleEvent describe una solicitud retrasada por el limitador de tasa.
+type ThrottleEvent struct {
+	// Op: Operación del cliente (por ejemplo, "CheckRUC").
+	Op string
+
+	// Method: Método HTTP de la solicitud.
+	Method string
+
+	// URL: URL de la solicitud.
+	URL string
+
+	// Wait: Tiempo que la solicitud esperó un turno.
+	Wait time.Duration
+}
+
+// Hooks son funciones que se invocan para observar los reintentos, el limitador de tasa
+// y los cambios en las respuestas del SRI. Pueden invocarse desde varias goroutines a la vez.
+type Hooks struct {
+	// OnRetry se invoca antes de esperar cada reintento.
+	OnRetry func(RetryEvent)
+
+	// OnThrottle se invoca cuando el limitador de tasa retrasa una solicitud.
+	OnThrottle func(ThrottleEvent)
+
+	// OnSchemaDrift se invoca cuando una respuesta no coincide con el tipo esperado. Los
+	// valores con formato no reconocido, como las fechas, se notifican siempre; los campos
+	// desconocidos o ausentes, solo con WithStrictDecoding.
+	OnSchemaDrift func(SchemaDriftEvent)
+}
+
+// retry notifica un reintento, si hay una función registrada.
+func (h Hooks) retry(event RetryEvent) {
+	if h.OnRetry != nil {
+		h.OnRetry(event)
+	}
+}
+
+// throttle notifica una solicitud retrasada, si hay una función registrada.
+func (h Hooks) throttle(event ThrottleEvent) {
+	if h.OnThrottle != nil {
+		h.OnThrottle(event)
+	}
+}
+
+// drift notifica una respuesta que no coincide con el tipo esperado, si hay una función
+// registrada.
+func (h Hooks) drift(event SchemaDriftEvent) {
+	if h.OnSchemaDrift != nil {
+		h.OnSchemaDrift(event)
+	}
+}
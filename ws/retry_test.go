@@ -0,0 +1,89 @@
+package ws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer crea un servidor que responde 503 a las primeras failures solicitudes.
+func newFlakyServer(failures int32, body string) (*httptest.Server, *atomic.Int32) {
+	var requests atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = w.Write([]byte(body))
+	}))
+
+	return server, &requests
+}
+
+var testRetryPolicy = RetryPolicy{Attempts: 3, Initial: time.Millisecond, Max: 2 * time.Millisecond, Multiplier: 2, Jitter: 0.5}
+
+func TestRetry(t *testing.T) {
+	server, requests := newFlakyServer(2, `true`)
+	defer server.Close()
+
+	var events []RetryEvent
+	service := NewSRIOnline(
+		WithBaseURL(server.URL),
+		WithRetry(testRetryPolicy),
+		WithHooks(Hooks{OnRetry: func(event RetryEvent) { events = append(events, event) }}),
+	)
+
+	exists, err := service.CheckRUC("0690000512001")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, int32(3), requests.Load())
+
+	require.Len(t, events, 2)
+	assert.Equal(t, 1, events[0].Attempt)
+	assert.Equal(t, http.StatusServiceUnavailable, events[0].StatusCode)
+	assert.Equal(t, http.MethodGet, events[1].Method)
+}
+
+func TestRetry_Exhausted(t *testing.T) {
+	server, requests := newFlakyServer(5, `true`)
+	defer server.Close()
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithRetry(testRetryPolicy))
+
+	_, err := service.CheckRUC("0690000512001")
+	assert.ErrorIs(t, err, ErrHTTPStatus)
+	assert.Equal(t, int32(3), requests.Load())
+}
+
+func TestRetry_NotIdempotent(t *testing.T) {
+	server, requests := newFlakyServer(1, receivedResponse)
+	defer server.Close()
+
+	service := NewSRIOnline(WithVouchersURL(sri.EnvTest, server.URL), WithRetry(testRetryPolicy))
+
+	_, err := service.ValidateVoucher(sri.EnvTest, []byte("<factura/>"))
+	assert.ErrorIs(t, err, ErrHTTPStatus)
+	assert.Equal(t, int32(1), requests.Load())
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	policy := RetryPolicy{Initial: time.Second, Max: 3 * time.Second, Multiplier: 2}
+	assert.Equal(t, time.Second, policy.delay(1))
+	assert.Equal(t, 2*time.Second, policy.delay(2))
+	assert.Equal(t, 3*time.Second, policy.delay(3))
+
+	policy.Jitter = 0.5
+	for range 10 {
+		delay := policy.delay(1)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, time.Second)
+	}
+}
@@ -0,0 +1,202 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pinzlab/sricore/id"
+)
+
+// Risk es el resultado de la evaluación de un proveedor.
+type Risk int
+
+const (
+	// RiskOK indica que no se encontraron observaciones.
+	RiskOK Risk = iota
+
+	// RiskWarn indica observaciones que deben revisarse antes de continuar.
+	RiskWarn
+
+	// RiskBlock indica que no se debe registrar al proveedor ni pagar sus comprobantes.
+	RiskBlock
+)
+
+// String devuelve el nombre del resultado.
+func (r Risk) String() string {
+	switch r {
+	case RiskWarn:
+		return "ADVERTENCIA"
+	case RiskBlock:
+		return "BLOQUEAR"
+	default:
+		return "OK"
+	}
+}
+
+// ReasonCode identifica el motivo de una observación.
+type ReasonCode string
+
+const (
+	ReasonInvalidRUC              ReasonCode = "RUC_INVALIDO"
+	ReasonNotRegistered           ReasonCode = "RUC_NO_REGISTRADO"
+	ReasonGhostTaxpayer           ReasonCode = "CONTRIBUYENTE_FANTASMA"
+	ReasonNonexistentTransactions ReasonCode = "TRANSACCIONES_INEXISTENTES"
+	ReasonSuspended               ReasonCode = "RUC_SUSPENDIDO"
+	ReasonPassive                 ReasonCode = "RUC_PASIVO"
+	ReasonUnknownStatus           ReasonCode = "ESTADO_DESCONOCIDO"
+	ReasonNoOpenEstablishment     ReasonCode = "SIN_ESTABLECIMIENTO_ABIERTO"
+)
+
+// Reason es una observación de la evaluación de un proveedor.
+type Reason struct {
+	// Code: Identificador del motivo.
+	Code ReasonCode
+
+	// Risk: Resultado que implica la observación.
+	Risk Risk
+
+	// Message: Descripción de la observación.
+	Message string
+}
+
+// ScreeningReport es el informe de la evaluación de un proveedor.
+type ScreeningReport struct {
+	// RUC: RUC evaluado.
+	RUC string
+
+	// Risk: Resultado de la evaluación; el más grave de sus observaciones.
+	Risk Risk
+
+	// Reasons: Observaciones encontradas.
+	Reasons []Reason
+
+	// Contributor: Información del contribuyente, o nil si no está registrado.
+	Contributor *Contributor
+
+	// Establishments: Establecimientos registrados del contribuyente.
+	Establishments []*Establishment
+
+	// CheckedAt: Fecha de la evaluación.
+	CheckedAt time.Time
+
+	// Source: Nombre del catastro que respondió la consulta (ver DataSource).
+	Source string
+
+	// DataAsOf: Fecha de los datos del SRI usados: la fecha en que se obtuvieron de los
+	// servicios en línea (también si provienen de la caché) o la de la última
+	// importación de un índice local. Es cero si no se consultó el catastro.
+	DataAsOf time.Time
+
+	// ContributorUpdatedAt: Fecha de la última actualización del RUC en el SRI.
+	ContributorUpdatedAt time.Time
+}
+
+// Blocked indica si el proveedor debe bloquearse.
+func (r *ScreeningReport) Blocked() bool {
+	return r.Risk == RiskBlock
+}
+
+// add registra una observación y actualiza el resultado.
+func (r *ScreeningReport) add(code ReasonCode, risk Risk, message string) {
+	r.Reasons = append(r.Reasons, Reason{Code: code, Risk: risk, Message: message})
+	if risk > r.Risk {
+		r.Risk = risk
+	}
+}
+
+// ScreenSupplier evalúa un proveedor consultando los servicios en línea del SRI.
+func (s *SRIOnline) ScreenSupplier(ruc string) (*ScreeningReport, error) {
+	return ScreenSupplier(context.Background(), s, ruc)
+}
+
+// ScreenSupplierContext es como ScreenSupplier, pero respeta la cancelación y el plazo
+// del contexto.
+func (s *SRIOnline) ScreenSupplierContext(ctx context.Context, ruc string) (*ScreeningReport, error) {
+	return ScreenSupplier(ctx, s, ruc)
+}
+
+// ScreenSupplier evalúa un proveedor antes de registrarlo o de pagar sus comprobantes de
+// compra, usando cualquier catastro (por ejemplo, un índice local).
+//
+// Se bloquean los RUC inválidos, no registrados, suspendidos, marcados como contribuyente
+// fantasma o con transacciones inexistentes. Se advierte de los RUC pasivos, con un estado
+// desconocido o sin establecimientos abiertos. Solo se devuelve un error si no se pudo
+// consultar el catastro.
+func ScreenSupplier(ctx context.Context, lookup ContributorLookup, ruc string) (*ScreeningReport, error) {
+	report := &ScreeningReport{RUC: ruc, CheckedAt: time.Now()}
+
+	if err := id.IsRUC(ruc); err != nil {
+		report.add(ReasonInvalidRUC, RiskBlock, err.Error())
+		return report, nil
+	}
+
+	contributors, source, err := contributorsSource(ctx, lookup, ruc)
+	if err != nil {
+		return nil, err
+	}
+	report.Source, report.DataAsOf = source.Name, source.AsOf
+
+	for _, contributor := range contributors {
+		if contributor != nil && contributor.Ruc == ruc {
+			report.Contributor = contributor
+			break
+		}
+	}
+
+	c := report.Contributor
+	if c == nil {
+		report.add(ReasonNotRegistered, RiskBlock, ErrContributorNotFound.Error())
+		return report, nil
+	}
+
+	report.ContributorUpdatedAt = c.TaxpayerDates.UpdateDate.Time
+
+	if c.IsGhostTaxpayer {
+		report.add(ReasonGhostTaxpayer, RiskBlock, "El SRI identifica al contribuyente como empresa fantasma o inexistente")
+	}
+
+	if c.NonexistentTransactions {
+		report.add(ReasonNonexistentTransactions, RiskBlock, "El SRI registra transacciones inexistentes del contribuyente")
+	}
+
+	switch ContributorStatus(normalize(string(c.Status))) {
+	case ContributorActive:
+	case ContributorSuspended:
+		report.add(ReasonSuspended, RiskBlock, withCancellationReason("El RUC está suspendido", c))
+	case ContributorPassive:
+		report.add(ReasonPassive, RiskWarn, withCancellationReason("El RUC está pasivo", c))
+	default:
+		report.add(ReasonUnknownStatus, RiskWarn, fmt.Sprintf("El estado del RUC %q no es reconocido", c.Status))
+	}
+
+	if report.Establishments, err = lookup.GetEstablishmentsContext(ctx, ruc); err != nil {
+		return nil, err
+	}
+
+	if !hasOpenEstablishment(report.Establishments) {
+		report.add(ReasonNoOpenEstablishment, RiskWarn, "El contribuyente no tiene establecimientos abiertos")
+	}
+
+	return report, nil
+}
+
+// withCancellationReason agrega al mensaje el motivo de cancelación o suspensión, si existe.
+func withCancellationReason(message string, c *Contributor) string {
+	if c.CancellationReason == nil || *c.CancellationReason == "" {
+		return message
+	}
+
+	return message + ": " + *c.CancellationReason
+}
+
+// hasOpenEstablishment indica si alguno de los establecimientos está abierto.
+func hasOpenEstablishment(establishments []*Establishment) bool {
+	for _, establishment := range establishments {
+		if establishment != nil && establishment.IsOpen() {
+			return true
+		}
+	}
+
+	return false
+}
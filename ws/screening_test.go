@@ -0,0 +1,116 @@
+package ws_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pinzlab/sricore/ws"
+)
+
+// datedLookup es un catastro local con fecha de actualización.
+type datedLookup struct {
+	staticLookup
+	updatedAt time.Time
+}
+
+func (l *datedLookup) UpdatedAt() time.Time {
+	return l.updatedAt
+}
+
+func TestSRIOnline_ScreenSupplier(t *testing.T) {
+	service, _ := newTestService(t)
+
+	report, err := service.ScreenSupplier(eersaRuc)
+	require.NoError(t, err)
+	assert.Equal(t, ws.RiskOK, report.Risk)
+	assert.Empty(t, report.Reasons)
+	assert.False(t, report.Blocked())
+	assert.Equal(t, eersaRuc, report.Contributor.Ruc)
+	assert.Len(t, report.Establishments, 1)
+	assert.Equal(t, ws.SourceSRIOnline, report.Source)
+	assert.WithinRange(t, report.DataAsOf, report.CheckedAt, time.Now())
+
+	report, err = service.ScreenSupplier("1790011674001")
+	require.NoError(t, err)
+	assert.True(t, report.Blocked())
+	require.Len(t, report.Reasons, 1)
+	assert.Equal(t, ws.ReasonNotRegistered, report.Reasons[0].Code)
+
+	report, err = service.ScreenSupplier("0690000512000")
+	require.NoError(t, err)
+	assert.True(t, report.Blocked())
+	assert.Equal(t, ws.ReasonInvalidRUC, report.Reasons[0].Code)
+}
+
+func TestScreenSupplier(t *testing.T) {
+	reason := "DEPURACION DEL RUC"
+	updatedAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	lookup := &datedLookup{updatedAt: updatedAt}
+	lookup.contributors = map[string]*ws.Contributor{
+		eersaRuc: {
+			Ruc:                     eersaRuc,
+			Status:                  ws.ContributorSuspended,
+			CancellationReason:      &reason,
+			IsGhostTaxpayer:         true,
+			NonexistentTransactions: true,
+		},
+		"1790011674001": {Ruc: "1790011674001", Status: ws.ContributorPassive},
+	}
+	lookup.establishments = map[string][]*ws.Establishment{
+		eersaRuc: {{Number: "001", Status: ws.EstablishmentOpen}},
+	}
+
+	report, err := ws.ScreenSupplier(context.Background(), lookup, eersaRuc)
+	require.NoError(t, err)
+	assert.Equal(t, ws.RiskBlock, report.Risk)
+	assert.Equal(t, updatedAt, report.DataAsOf)
+
+	var codes []ws.ReasonCode
+	for _, r := range report.Reasons {
+		codes = append(codes, r.Code)
+	}
+	assert.Equal(t, []ws.ReasonCode{ws.ReasonGhostTaxpayer, ws.ReasonNonexistentTransactions, ws.ReasonSuspended}, codes)
+	assert.Equal(t, "El RUC está suspendido: DEPURACION DEL RUC", report.Reasons[2].Message)
+
+	report, err = ws.ScreenSupplier(context.Background(), lookup, "1790011674001")
+	require.NoError(t, err)
+	assert.Equal(t, ws.RiskWarn, report.Risk)
+	assert.Equal(t, "ADVERTENCIA", report.Risk.String())
+	require.Len(t, report.Reasons, 2)
+	assert.Equal(t, ws.ReasonPassive, report.Reasons[0].Code)
+	assert.Equal(t, ws.ReasonNoOpenEstablishment, report.Reasons[1].Code)
+}
+
+func TestScreenSupplier_Fallback(t *testing.T) {
+	service, server := newTestService(t)
+	updatedAt := time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)
+	secondary := &datedLookup{updatedAt: updatedAt}
+	secondary.contributors = map[string]*ws.Contributor{eersaRuc: {Ruc: eersaRuc, Status: ws.ContributorActive}}
+
+	lookup := ws.NewFallbackLookup(service, secondary)
+
+	report, err := ws.ScreenSupplier(context.Background(), lookup, eersaRuc)
+	require.NoError(t, err)
+	assert.Equal(t, ws.SourceSRIOnline, report.Source)
+	assert.WithinRange(t, report.DataAsOf, report.CheckedAt, time.Now())
+
+	// Con el SRI caído, la fecha de los datos es la del catastro secundario.
+	server.Outage(-1)
+
+	report, err = ws.ScreenSupplier(context.Background(), lookup, eersaRuc)
+	require.NoError(t, err)
+	assert.Empty(t, report.Source)
+	assert.Equal(t, updatedAt, report.DataAsOf)
+}
+
+func TestScreenSupplier_Unavailable(t *testing.T) {
+	service, server := newTestService(t)
+	server.Outage(-1)
+
+	_, err := service.ScreenSupplierContext(context.Background(), eersaRuc)
+	assert.ErrorIs(t, err, ws.ErrHTTPStatus)
+}
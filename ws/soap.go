@@ -0,0 +1,102 @@
+package ws
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"net/http"
+)
+
+// soapFault representa un error SOAP devuelto por los servicios web del SRI.
+type soapFault struct {
+	// Code: Código del error SOAP.
+	Code string `xml:"faultcode"`
+
+	// Message: Descripción del error SOAP.
+	Message string `xml:"faultstring"`
+}
+
+// soapEnvelope es el sobre SOAP de una respuesta cuyo contenido es de tipo T.
+type soapEnvelope[T any] struct {
+	Body struct {
+		Fault   *soapFault `xml:"Fault"`
+		Content T          `xml:",any"`
+	} `xml:"Body"`
+}
+
+// soapRequest construye el sobre SOAP 1.1 para invocar una operación de los servicios
+// web de comprobantes electrónicos.
+//
+// Ejemplo:
+//
+//	soapRequest("http://ec.gob.sri.ws.recepcion", "validarComprobante", "xml", base64XML)
+func soapRequest(namespace, operation, param, value string) []byte {
+	var b bytes.Buffer
+
+	b.WriteString(`<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/" xmlns:ec="` + namespace + `">`)
+	b.WriteString(`<soapenv:Header/><soapenv:Body>`)
+	b.WriteString(`<ec:` + operation + `><` + param + `>`)
+	_ = xml.EscapeText(&b, []byte(value))
+	b.WriteString(`</` + param + `></ec:` + operation + `>`)
+	b.WriteString(`</soapenv:Body></soapenv:Envelope>`)
+
+	return b.Bytes()
+}
+
+// post envía un sobre SOAP a la URL indicada y deserializa el contenido del cuerpo de
+// la respuesta en el tipo especificado.
+//
+// Parameters:
+//
+//	ctx: Contexto de la solicitud; su cancelación o plazo interrumpen la solicitud.
+//	s: Cliente SRIOnline con la configuración HTTP (cliente, cabeceras y tiempo máximo).
+//	op: Nombre de la operación, usado en los errores y registros.
+//	url: URL del servicio web.
+//	body: Sobre SOAP de la solicitud.
+//	idempotent: Indica si la solicitud puede reintentarse sin efectos adicionales.
+//
+// Returns:
+//   - Un valor deserializado del tipo indicado (T).
+//   - Un *Error si ocurre algún problema durante la solicitud, si el servicio responde
+//     con un SOAP Fault o si la respuesta no puede deserializarse.
+func post[T any](ctx context.Context, s *SRIOnline, op, url string, body []byte, idempotent bool) (T, error) {
+	var result T
+
+	err := s.observe(ctx, op, http.MethodPost, url, func(ctx context.Context) (err error) {
+		result, err = exchangeSOAP[T](ctx, s, op, url, body, idempotent)
+		return err
+	})
+
+	return result, err
+}
+
+// exchangeSOAP envía un sobre SOAP y deserializa el contenido de la respuesta.
+func exchangeSOAP[T any](ctx context.Context, s *SRIOnline, op, url string, body []byte, idempotent bool) (T, error) {
+	var result T
+
+	status, data, err := s.exchange(ctx, op, http.MethodPost, url, "text/xml; charset=utf-8", body, idempotent)
+	if err != nil {
+		return result, err
+	}
+
+	var envelope soapEnvelope[T]
+	if err := xml.Unmarshal(data, &envelope); err != nil {
+		if status != http.StatusOK {
+			return result, s.fail(op, url, status, data, ErrHTTPStatus, nil)
+		}
+
+		return result, s.fail(op, url, status, data, ErrXMLUnmarshal, err)
+	}
+
+	// Los SOAP Fault se devuelven con estado 500
+	if fault := envelope.Body.Fault; fault != nil {
+		return result, s.fail(op, url, status, data, ErrSOAPFault, errors.New(fault.Message))
+	}
+
+	if status != http.StatusOK {
+		return result, s.fail(op, url, status, data, ErrHTTPStatus, nil)
+	}
+
+	return envelope.Body.Content, nil
+}
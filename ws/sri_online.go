@@ -1,25 +1,225 @@
 package ws
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"log/slog"
 	"net/http"
+	"time"
+
+	"github.com/pinzlab/sricore/sri"
 )
 
 const (
 	sriOnline      string = "https://srienlinea.sri.gob.ec"
 	sriContributor string = "/sri-catastro-sujeto-servicio-internet/rest"
+	sriVehicle     string = "/sri-matriculacion-vehicular-recaudacion-servicio-internet/rest"
+
+	sriVouchersTest string = "https://celcer.sri.gob.ec"
+	sriVouchersProd string = "https://cel.sri.gob.ec"
+	sriVouchers     string = "/comprobantes-electronicos-ws"
+
+	// DefaultTimeout es el tiempo máximo de cada solicitud al SRI.
+	DefaultTimeout = 30 * time.Second
+
+	// DefaultUserAgent es el User-Agent enviado al SRI.
+	DefaultUserAgent = "sricore"
 )
 
 // SRIOnline es un cliente para interactuar con los servicios del SRI de Ecuador.
 type SRIOnline struct {
 	client *http.Client
+
+	// transport reemplaza el RoundTripper del cliente, si se indicó con WithTransport.
+	transport http.RoundTripper
+
+	// baseURL es la URI base del servicio de contribuyentes (SRI en línea).
+	baseURL string
+
+	// vouchersURL contiene la URI base de los servicios web de comprobantes por ambiente.
+	vouchersURL map[sri.EnvType]string
+
+	// timeout es el tiempo máximo de cada solicitud; cero desactiva el límite.
+	timeout time.Duration
+
+	// header contiene las cabeceras enviadas en cada solicitud.
+	header http.Header
+
+	// retry es la política de reintentos de las solicitudes idempotentes.
+	retry RetryPolicy
+
+	// limiter limita la tasa de solicitudes; nil no aplica límite.
+	limiter *RateLimiter
+
+	// hooks permite observar los reintentos y el limitador de tasa.
+	hooks Hooks
+
+	// logger registra las solicitudes y los errores; por defecto no registra nada.
+	logger *slog.Logger
+
+	// cache almacena las consultas del catastro; nil desactiva la caché.
+	cache Cache
+
+	// cacheTTL es el tiempo de vida de las respuestas con resultados.
+	cacheTTL time.Duration
+
+	// negativeTTL es el tiempo de vida de las respuestas sin resultados (RUC inexistente).
+	negativeTTL time.Duration
+
+	// flight agrupa las consultas idénticas en curso cuando la caché está activa.
+	flight flight
+
+	// strict compara las respuestas JSON con los tipos esperados y notifica las diferencias.
+	strict bool
+
+	// instrumentation registra métricas y trazas de las llamadas; nil no registra nada.
+	instrumentation Instrumentation
+
+	// breaker corta las solicitudes a los servicios no disponibles; nil no las corta.
+	breaker *CircuitBreaker
+}
+
+// Option configura una instancia de SRIOnline.
+type Option func(*SRIOnline)
+
+// WithHTTPClient usa el cliente HTTP indicado para todas las solicitudes al SRI. Un
+// cliente nil se ignora.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *SRIOnline) {
+		if client != nil {
+			s.client = client
+		}
+	}
+}
+
+// WithTransport usa el RoundTripper indicado, por ejemplo para un proxy corporativo o
+// un conjunto de CA propio. Se aplica sobre una copia del cliente configurado, de modo
+// que se conservan el tiempo máximo, las cookies y la política de redirecciones de un
+// cliente indicado con WithHTTPClient, en cualquier orden.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(s *SRIOnline) {
+		s.transport = transport
+	}
+}
+
+// WithBaseURL reemplaza la URI base del servicio de contribuyentes
+// (por defecto https://srienlinea.sri.gob.ec).
+func WithBaseURL(url string) Option {
+	return func(s *SRIOnline) {
+		s.baseURL = url
+	}
+}
+
+// WithVouchersURL reemplaza la URI base de los servicios web de comprobantes del
+// ambiente indicado (por defecto https://celcer.sri.gob.ec y https://cel.sri.gob.ec).
+func WithVouchersURL(env sri.EnvType, url string) Option {
+	return func(s *SRIOnline) {
+		s.vouchersURL[env] = url
+	}
+}
+
+// WithTimeout define el tiempo máximo de cada solicitud. Cero desactiva el límite;
+// el contexto de la solicitud puede imponer uno menor.
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *SRIOnline) {
+		s.timeout = timeout
+	}
+}
+
+// WithHeader agrega una cabecera a todas las solicitudes.
+func WithHeader(key, value string) Option {
+	return func(s *SRIOnline) {
+		s.header.Add(key, value)
+	}
+}
+
+// WithUserAgent reemplaza el User-Agent enviado al SRI.
+func WithUserAgent(userAgent string) Option {
+	return func(s *SRIOnline) {
+		s.header.Set("User-Agent", userAgent)
+	}
+}
+
+// WithRetry define la política de reintentos de las solicitudes idempotentes. Por
+// defecto no se reintenta; DefaultRetryPolicy es una política recomendada.
+func WithRetry(policy RetryPolicy) Option {
+	return func(s *SRIOnline) {
+		s.retry = policy
+	}
+}
+
+// WithRateLimiter limita la tasa de solicitudes al SRI. El mismo limitador puede
+// compartirse entre varios clientes.
+func WithRateLimiter(limiter *RateLimiter) Option {
+	return func(s *SRIOnline) {
+		s.limiter = limiter
+	}
+}
+
+// WithHooks registra funciones para observar los reintentos y el limitador de tasa.
+func WithHooks(hooks Hooks) Option {
+	return func(s *SRIOnline) {
+		s.hooks = hooks
+	}
+}
+
+// WithLogger registra las solicitudes y los errores en el logger indicado. Los errores
+// se registran con nivel Warn y los reintentos y respuestas con nivel Debug.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *SRIOnline) {
+		if logger != nil {
+			s.logger = logger
+		}
+	}
+}
+
+// WithStrictDecoding compara cada respuesta JSON con el tipo esperado y notifica los
+// campos desconocidos o ausentes mediante Hooks.OnSchemaDrift y el logger (nivel Warn),
+// para detectar cambios en los servicios del SRI. Las diferencias no producen errores.
+func WithStrictDecoding() Option {
+	return func(s *SRIOnline) {
+		s.strict = true
+	}
+}
+
+// WithCache almacena las consultas del catastro en la caché indicada. Las respuestas con
+// resultados se conservan durante ttl y las de RUC inexistentes (o sin establecimientos)
+// durante negativeTTL; un tiempo de cero no almacena la respuesta. Con la caché activa,
+// las consultas idénticas simultáneas se agrupan en una sola solicitud al SRI.
+func WithCache(cache Cache, ttl, negativeTTL time.Duration) Option {
+	return func(s *SRIOnline) {
+		s.cache = cache
+		s.cacheTTL = ttl
+		s.negativeTTL = negativeTTL
+	}
 }
 
 // NewSRIOnline crea una nueva instancia de SRIOnline con la URI base y un cliente HTTP.
-func NewSRIOnline() *SRIOnline {
-	return &SRIOnline{
-		client: &http.Client{},
+func NewSRIOnline(opts ...Option) *SRIOnline {
+	s := &SRIOnline{
+		client:  &http.Client{},
+		baseURL: sriOnline,
+		vouchersURL: map[sri.EnvType]string{
+			sri.EnvTest: sriVouchersTest,
+			sri.EnvProd: sriVouchersProd,
+		},
+		timeout: DefaultTimeout,
+		header:  http.Header{"User-Agent": {DefaultUserAgent}},
+		logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
 	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.transport != nil {
+		client := *s.client
+		client.Transport = s.transport
+		s.client = &client
+	}
+
+	return s
 }
 
 // contributorURL construye una URL completa para un endpoint del servicio de contribuyentes.
@@ -28,5 +228,55 @@ func NewSRIOnline() *SRIOnline {
 //
 //	s.contributorURL("/Establecimiento/consultarPorNumeroRuc?numeroRuc=%s", "1790016919001")
 func (s *SRIOnline) contributorURL(endpoint string, args ...any) string {
-	return fmt.Sprintf(sriOnline+sriContributor+endpoint, args...)
+	return fmt.Sprintf(s.baseURL+sriContributor+endpoint, args...)
+}
+
+// vehicleURL construye una URL completa para un endpoint del servicio de matriculación
+// vehicular.
+//
+// Ejemplo:
+//
+//	s.vehicleURL("/ConsultaRubros/obtenerPorCodigoVehiculo?codigoVehiculo=%d", 1234567)
+func (s *SRIOnline) vehicleURL(endpoint string, args ...any) string {
+	return fmt.Sprintf(s.baseURL+sriVehicle+endpoint, args...)
+}
+
+// voucherURL construye la URL de un servicio web de comprobantes electrónicos para
+// el ambiente indicado.
+//
+// Ejemplo:
+//
+//	s.voucherURL(sri.EnvTest, "/RecepcionComprobantesOffline")
+func (s *SRIOnline) voucherURL(env sri.EnvType, service string) (string, error) {
+	base, ok := s.vouchersURL[env]
+	if !ok {
+		return "", ErrInvalidEnv
+	}
+
+	return base + sriVouchers + service, nil
+}
+
+// withTimeout aplica el tiempo máximo configurado al contexto de una solicitud.
+func (s *SRIOnline) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// newRequest crea una solicitud HTTP con las cabeceras configuradas.
+func (s *SRIOnline) newRequest(ctx context.Context, method, url string, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range s.header {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	return req, nil
 }
@@ -0,0 +1,121 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSRIOnline_Options(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, sriContributor+"/ConsolidadoContribuyente/existePorNumeroRuc", r.URL.Path)
+		assert.Equal(t, "facturador/1.0", r.Header.Get("User-Agent"))
+		assert.Equal(t, "valor", r.Header.Get("X-Prueba"))
+
+		_, _ = w.Write([]byte(`true`))
+	}))
+	defer server.Close()
+
+	service := NewSRIOnline(
+		WithBaseURL(server.URL),
+		WithTransport(server.Client().Transport),
+		WithUserAgent("facturador/1.0"),
+		WithHeader("X-Prueba", "valor"),
+	)
+
+	exists, err := service.CheckRUC("0690000512001")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestNewSRIOnline_Defaults(t *testing.T) {
+	service := NewSRIOnline()
+
+	assert.Equal(t, sriOnline, service.baseURL)
+	assert.Equal(t, DefaultTimeout, service.timeout)
+	assert.Equal(t, DefaultUserAgent, service.header.Get("User-Agent"))
+}
+
+func TestWithHTTPClient_Nil(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`true`))
+	}))
+	defer server.Close()
+
+	service := NewSRIOnline(WithHTTPClient(nil), WithBaseURL(server.URL))
+	require.NotNil(t, service.client)
+
+	exists, err := service.CheckRUC("0690000512001")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+// roundTripperFunc adapta una función a http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestWithTransport_HTTPClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`true`))
+	}))
+	defer server.Close()
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	client := &http.Client{Timeout: 7 * time.Second, Jar: jar}
+
+	var proxied int
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		proxied++
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	for _, opts := range [][]Option{
+		{WithHTTPClient(client), WithTransport(transport)},
+		{WithTransport(transport), WithHTTPClient(client)},
+	} {
+		service := NewSRIOnline(append(opts, WithBaseURL(server.URL))...)
+		assert.Equal(t, 7*time.Second, service.client.Timeout)
+		assert.Same(t, jar, service.client.Jar)
+
+		exists, err := service.CheckRUC("0690000512001")
+		require.NoError(t, err)
+		assert.True(t, exists)
+	}
+
+	assert.Equal(t, 2, proxied)
+	assert.Nil(t, client.Transport, "the caller's client must not be modified")
+}
+
+func TestSRIOnline_Timeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithTimeout(20*time.Millisecond))
+
+	_, err := service.GetContributors("0690000512001")
+	assert.ErrorIs(t, err, ErrHTTPRequest)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = NewSRIOnline(WithBaseURL(server.URL)).GetEstablishmentsContext(ctx, "0690000512001")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,286 @@
+package ws
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// SchemaDriftEvent describe las diferencias entre una respuesta JSON del SRI y el tipo
+// con el que se deserializó.
+//
+// Las rutas usan la notación de los campos JSON: "[]" representa cualquier elemento de
+// una lista, por ejemplo "[].informacionFechasContribuyente.fechaCese".
+type SchemaDriftEvent struct {
+	// Op: Operación del cliente (por ejemplo, "GetContributors").
+	Op string
+
+	// URL: URL de la solicitud.
+	URL string
+
+	// Unknown: Campos de la respuesta que el tipo no contiene.
+	Unknown []string
+
+	// Missing: Campos del tipo que la respuesta no contiene.
+	Missing []string
+
+	// Invalid: Campos con valores que no tienen el formato esperado, como las fechas no
+	// reconocidas que se conservan en Date.Raw.
+	Invalid []string
+}
+
+var (
+	jsonUnmarshaler = reflect.TypeFor[json.Unmarshaler]()
+	textUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+	dateType        = reflect.TypeFor[Date]()
+)
+
+// checkSchema compara el cuerpo de una respuesta con el tipo T y notifica las
+// diferencias. Los campos desconocidos o ausentes solo se buscan cuando el modo estricto
+// está activo; las fechas no reconocidas, que Date.UnmarshalJSON conserva en Date.Raw al
+// deserializar result, se notifican siempre.
+func checkSchema[T any](s *SRIOnline, op, url string, body []byte, result T) {
+	drift := &schemaDrift{unknown: map[string]bool{}, missing: map[string]bool{}, invalid: map[string]bool{}}
+
+	if hasDates(reflect.TypeFor[T](), map[reflect.Type]bool{}) {
+		drift.invalidDates("", reflect.ValueOf(&result).Elem())
+	}
+
+	if s.strict {
+		decoder := json.NewDecoder(bytes.NewReader(body))
+		decoder.UseNumber()
+
+		var value any
+		if err := decoder.Decode(&value); err == nil {
+			drift.compare("", reflect.TypeFor[T](), value)
+		}
+	}
+
+	if len(drift.unknown) == 0 && len(drift.missing) == 0 && len(drift.invalid) == 0 {
+		return
+	}
+
+	s.reportDrift(SchemaDriftEvent{
+		Op:      op,
+		URL:     url,
+		Unknown: sortedKeys(drift.unknown),
+		Missing: sortedKeys(drift.missing),
+		Invalid: sortedKeys(drift.invalid),
+	})
+}
+
+// reportDrift registra y notifica una respuesta que no coincide con el tipo esperado.
+func (s *SRIOnline) reportDrift(event SchemaDriftEvent) {
+	s.logger.Warn("SRI response does not match the expected schema", "op", event.Op, "url", event.URL, "unknown", event.Unknown, "missing", event.Missing, "invalid", event.Invalid)
+	s.hooks.drift(event)
+}
+
+// schemaDrift acumula las diferencias encontradas, sin repetir rutas.
+type schemaDrift struct {
+	unknown map[string]bool
+	missing map[string]bool
+	invalid map[string]bool
+}
+
+// compare recorre un valor JSON junto con el tipo Go en el que se deserializa.
+func (d *schemaDrift) compare(path string, t reflect.Type, value any) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if value == nil {
+		return
+	}
+
+	if decodesItself(t) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		object, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		fields := jsonFields(t)
+		for name, field := range fields {
+			child, present := object[name]
+			if !present {
+				if !field.optional {
+					d.missing[join(path, name)] = true
+				}
+				continue
+			}
+
+			d.compare(join(path, name), field.typ, child)
+		}
+
+		for name := range object {
+			if _, ok := fields[name]; !ok {
+				d.unknown[join(path, name)] = true
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if items, ok := value.([]any); ok {
+			for _, item := range items {
+				d.compare(path+"[]", t.Elem(), item)
+			}
+		}
+	case reflect.Map:
+		if object, ok := value.(map[string]any); ok {
+			for _, item := range object {
+				d.compare(path+"{}", t.Elem(), item)
+			}
+		}
+	}
+}
+
+// invalidDates recorre un valor deserializado y registra las rutas de las fechas que
+// Date.UnmarshalJSON no reconoció.
+func (d *schemaDrift) invalidDates(path string, v reflect.Value) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == dateType {
+		if v.FieldByName("Raw").String() != "" {
+			d.invalid[path] = true
+		}
+		return
+	}
+
+	if decodesItself(v.Type()) {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		for name, field := range jsonFields(v.Type()) {
+			if child, err := v.FieldByIndexErr(field.index); err == nil {
+				d.invalidDates(join(path, name), child)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			d.invalidDates(path+"[]", v.Index(i))
+		}
+	case reflect.Map:
+		for iter := v.MapRange(); iter.Next(); {
+			d.invalidDates(path+"{}", iter.Value())
+		}
+	}
+}
+
+// jsonField es un campo de un struct según encoding/json.
+type jsonField struct {
+	typ      reflect.Type
+	index    []int
+	optional bool
+}
+
+// jsonFields devuelve los campos JSON de un struct, incluidos los de los structs
+// embebidos sin etiqueta.
+func jsonFields(t reflect.Type) map[string]jsonField {
+	fields := map[string]jsonField{}
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+
+			if embedded.Kind() == reflect.Struct {
+				for name, f := range jsonFields(embedded) {
+					if _, exists := fields[name]; !exists {
+						f.index = append([]int{i}, f.index...)
+						fields[name] = f
+					}
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		fields[name] = jsonField{typ: field.Type, index: []int{i}, optional: slices.Contains(strings.Split(options, ","), "omitempty")}
+	}
+
+	return fields
+}
+
+// hasDates indica si el tipo contiene fechas del catastro, directamente o en sus campos,
+// listas o mapas.
+func hasDates(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == dateType {
+		return true
+	}
+	if seen[t] || decodesItself(t) {
+		return false
+	}
+	seen[t] = true
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for _, field := range jsonFields(t) {
+			if hasDates(field.typ, seen) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasDates(t.Elem(), seen)
+	}
+
+	return false
+}
+
+// decodesItself indica si el tipo tiene su propio deserializado, como Date o sri.Bool.
+func decodesItself(t reflect.Type) bool {
+	pointer := reflect.PointerTo(t)
+	return t.Implements(jsonUnmarshaler) || pointer.Implements(jsonUnmarshaler) ||
+		t.Implements(textUnmarshaler) || pointer.Implements(textUnmarshaler)
+}
+
+// join agrega un campo a una ruta JSON.
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// sortedKeys devuelve las claves de un conjunto ordenadas.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
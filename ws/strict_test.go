@@ -0,0 +1,185 @@
+package ws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDriftServer responde con un contribuyente al que le falta un campo y tiene otro nuevo.
+func newDriftServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{
+			"numeroRuc": "0690000512001",
+			"razonSocial": "EMPRESA ELECTRICA RIOBAMBA SA",
+			"estadoContribuyenteRuc": "ACTIVO",
+			"actividadEconomicaPrincipal": "GENERACION DE ENERGIA",
+			"tipoContribuyente": "SOCIEDAD",
+			"regimen": "GENERAL",
+			"categoria": null,
+			"obligadoLlevarContabilidad": "SI",
+			"agenteRetencion": "SI",
+			"contribuyenteEspecial": "SI",
+			"informacionFechasContribuyente": {
+				"fechaInicioActividades": "1963-04-03 00:00:00.0",
+				"fechaCese": "",
+				"fechaActualizacion": "2023-05-10 10:15:20.0",
+				"fechaSuspension": null
+			},
+			"representantesLegales": [{"identificacion": "0601234560", "nombre": "PEREZ JUAN", "cargo": "GERENTE"}],
+			"motivoCancelacionSuspension": null,
+			"contribuyenteFantasma": "NO",
+			"transaccionesInexistente": "NO",
+			"correoElectronico": "info@eersa.com.ec"
+		}]`))
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestStrictDecoding(t *testing.T) {
+	server := newDriftServer(t)
+
+	var mu sync.Mutex
+	var events []SchemaDriftEvent
+	hooks := Hooks{OnSchemaDrift: func(event SchemaDriftEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}}
+
+	service := NewSRIOnline(WithBaseURL(server.URL), WithHooks(hooks), WithStrictDecoding())
+
+	contributors, err := service.GetContributors("0690000512001")
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+
+	require.Len(t, events, 1)
+	assert.Equal(t, "GetContributors", events[0].Op)
+	assert.Equal(t, []string{
+		"[].correoElectronico",
+		"[].informacionFechasContribuyente.fechaSuspension",
+		"[].representantesLegales[].cargo",
+	}, events[0].Unknown)
+	assert.Equal(t, []string{"[].informacionFechasContribuyente.fechaReinicioActividades"}, events[0].Missing)
+}
+
+func TestStrictDecoding_Disabled(t *testing.T) {
+	server := newDriftServer(t)
+
+	called := false
+	service := NewSRIOnline(WithBaseURL(server.URL), WithHooks(Hooks{OnSchemaDrift: func(SchemaDriftEvent) { called = true }}))
+
+	_, err := service.GetContributors("0690000512001")
+	require.NoError(t, err)
+	assert.False(t, called)
+}
+
+func TestStrictDecoding_Cached(t *testing.T) {
+	server := newDriftServer(t)
+
+	count := 0
+	service := NewSRIOnline(
+		WithBaseURL(server.URL),
+		WithStrictDecoding(),
+		WithHooks(Hooks{OnSchemaDrift: func(SchemaDriftEvent) { count++ }}),
+		WithCache(NewMemoryCache(), time.Minute, time.Minute),
+	)
+
+	for range 3 {
+		_, err := service.GetContributorsContext(context.Background(), "0690000512001")
+		require.NoError(t, err)
+	}
+
+	// Las respuestas de la caché no se vuelven a comparar.
+	assert.Equal(t, 1, count)
+}
+
+func TestSchemaDrift_Compare(t *testing.T) {
+	type inner struct {
+		Value string `json:"value"`
+	}
+	type sample struct {
+		inner
+		Name     string            `json:"name"`
+		Optional string            `json:"optional,omitempty"`
+		Skipped  string            `json:"-"`
+		Items    map[string]*inner `json:"items"`
+	}
+
+	drift := &schemaDrift{unknown: map[string]bool{}, missing: map[string]bool{}}
+	drift.compare("", reflect.TypeFor[sample](), map[string]any{
+		"value": "x",
+		"items": map[string]any{"a": map[string]any{"value": "y", "extra": true}},
+		"-":     "z",
+	})
+
+	assert.Equal(t, []string{"-", "items{}.extra"}, sortedKeys(drift.unknown))
+	assert.Equal(t, []string{"name"}, sortedKeys(drift.missing))
+}
+
+func TestStrictDecoding_InvalidDate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{
+			"numeroRuc": "0690000512001",
+			"razonSocial": "EMPRESA ELECTRICA RIOBAMBA SA",
+			"informacionFechasContribuyente": {
+				"fechaInicioActividades": "03 de abril de 1963",
+				"fechaActualizacion": "2023-05-10 10:15:20.0"
+			}
+		}]`))
+	}))
+	defer server.Close()
+
+	var events []SchemaDriftEvent
+	hooks := Hooks{OnSchemaDrift: func(event SchemaDriftEvent) { events = append(events, event) }}
+
+	// Las fechas no reconocidas se notifican aunque el modo estricto no esté activo
+	service := NewSRIOnline(WithBaseURL(server.URL), WithHooks(hooks))
+
+	contributors, err := service.GetContributors("0690000512001")
+	require.NoError(t, err)
+	require.Len(t, contributors, 1)
+
+	dates := contributors[0].TaxpayerDates
+	assert.True(t, dates.StartDate.IsZero())
+	assert.Equal(t, "03 de abril de 1963", dates.StartDate.Raw)
+	assert.Equal(t, 2023, dates.UpdateDate.Year())
+
+	require.Len(t, events, 1)
+	assert.Equal(t, []string{"[].informacionFechasContribuyente.fechaInicioActividades"}, events[0].Invalid)
+	assert.Empty(t, events[0].Unknown)
+	assert.Empty(t, events[0].Missing)
+}
+
+func TestSchemaDrift_InvalidDates(t *testing.T) {
+	type dates struct {
+		Start Date `json:"start"`
+	}
+	type sample struct {
+		dates
+		End   *Date            `json:"end"`
+		Items []dates          `json:"items"`
+		ByKey map[string]dates `json:"byKey"`
+	}
+
+	value := sample{
+		dates: dates{Start: Date{Raw: "ayer"}},
+		Items: []dates{{}, {Start: Date{Raw: "mañana"}}},
+		ByKey: map[string]dates{"a": {Start: Date{Raw: "hoy"}}},
+	}
+
+	drift := &schemaDrift{invalid: map[string]bool{}}
+	drift.invalidDates("", reflect.ValueOf(value))
+
+	assert.Equal(t, []string{"byKey{}.start", "items[].start", "start"}, sortedKeys(drift.invalid))
+}
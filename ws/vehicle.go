@@ -0,0 +1,189 @@
+package ws
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pinzlab/sricore/sri"
+)
+
+// Vehicle contiene los datos de un vehículo registrado para la matriculación vehicular.
+type Vehicle struct {
+	// Code: Código interno del vehículo en el SRI, usado para consultar sus valores.
+	Code int64 `json:"codigoVehiculo"`
+
+	// Plate: Número de placa.
+	Plate string `json:"numeroPlaca"`
+
+	// CAMV: Código de Asignación de Matrícula Vehicular (CAMV) o Código de Producto
+	// Nuevo (CPN).
+	CAMV string `json:"numeroCamvCpn"`
+
+	// Brand: Marca del vehículo.
+	Brand string `json:"descripcionMarca"`
+
+	// Model: Modelo del vehículo.
+	Model string `json:"descripcionModelo"`
+
+	// Year: Año de fabricación.
+	Year int `json:"anioAuto"`
+
+	// Country: País de fabricación.
+	Country string `json:"descripcionPais"`
+
+	// Color: Color principal.
+	Color string `json:"colorVehiculo1"`
+
+	// SecondaryColor: Color secundario, si existe.
+	SecondaryColor string `json:"colorVehiculo2"`
+
+	// Displacement: Cilindraje del motor en centímetros cúbicos.
+	Displacement int `json:"cilindraje"`
+
+	// Class: Clase del vehículo (AUTOMÓVIL, MOTOCICLETA, CAMIONETA, ...).
+	Class string `json:"nombreClase"`
+
+	// Service: Tipo de servicio (PARTICULAR, PÚBLICO, ...).
+	Service string `json:"descripcionServicio"`
+
+	// Canton: Cantón en el que está registrado el vehículo.
+	Canton string `json:"descripcionCanton"`
+
+	// LastPaidYear: Último año de matriculación pagado.
+	LastPaidYear int `json:"ultimoAnioPagado"`
+
+	// PurchaseDate: Fecha de compra registrada.
+	PurchaseDate Date `json:"fechaCompraRegistro"`
+
+	// LastRegistration: Fecha de la última matrícula.
+	LastRegistration Date `json:"fechaUltimaMatricula"`
+
+	// RegistrationExpiry: Fecha de caducidad de la matrícula.
+	RegistrationExpiry Date `json:"fechaCaducidadMatricula"`
+
+	// SaleProhibited: Indica si el vehículo tiene prohibición de enajenar.
+	SaleProhibited sri.Bool `json:"prohibidoEnajenar"`
+
+	// Remarks: Observaciones registradas sobre el vehículo (si aplica).
+	Remarks *string `json:"observacion"`
+}
+
+// VehicleFee es un valor pendiente de pago de un vehículo: impuestos, tasas o multas.
+type VehicleFee struct {
+	// Description: Descripción del rubro, por ejemplo "IMPUESTO A LA PROPIEDAD DE VEHICULOS".
+	Description string `json:"descripcionRubro"`
+
+	// Beneficiary: Institución que recibe el valor (SRI, ANT, GAD municipal, ...).
+	Beneficiary string `json:"nombreBeneficiario"`
+
+	// FiscalYear: Año fiscal al que corresponde el valor.
+	FiscalYear int `json:"periodoFiscal"`
+
+	// Amount: Valor a pagar en dólares.
+	Amount float64 `json:"valor"`
+}
+
+// VehicleDebts contiene los datos de un vehículo y sus valores pendientes.
+type VehicleDebts struct {
+	// Vehicle: Datos del vehículo.
+	Vehicle *Vehicle
+
+	// Fees: Valores pendientes de pago.
+	Fees []*VehicleFee
+
+	// Total: Suma de los valores pendientes, redondeada a centavos.
+	Total float64
+}
+
+// GetVehicle obtiene los datos de un vehículo por su placa, CAMV o CPN.
+//
+// Este endpoint en parte del API oficial del SRI, pero no están documentados públicamente.
+// Su uso puede estar sujeto a cambios o restricciones sin previo aviso.
+func (s *SRIOnline) GetVehicle(plate string) (*Vehicle, error) {
+	return s.GetVehicleContext(context.Background(), plate)
+}
+
+// GetVehicleContext es como GetVehicle, pero respeta la cancelación y el plazo del
+// contexto.
+func (s *SRIOnline) GetVehicleContext(ctx context.Context, plate string) (*Vehicle, error) {
+	value, err := normalizePlate(plate)
+	if err != nil {
+		return nil, err
+	}
+
+	url := s.vehicleURL("/BaseVehiculo/obtenerPorNumeroPlacaOPorNumeroCampvOPorNumeroCpn?numeroPlacaCampvCpn=%s", value)
+
+	vehicle, err := cached(ctx, s, "GetVehicle", url, func(v *Vehicle) bool { return v == nil || v.Code == 0 })
+	if err != nil {
+		return nil, err
+	}
+
+	if vehicle == nil || vehicle.Code == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrVehicleNotFound, value)
+	}
+
+	return vehicle, nil
+}
+
+// GetVehicleFees obtiene los valores pendientes de pago de un vehículo a partir de su
+// código interno (Vehicle.Code).
+//
+// Este endpoint en parte del API oficial del SRI, pero no están documentados públicamente.
+// Su uso puede estar sujeto a cambios o restricciones sin previo aviso.
+func (s *SRIOnline) GetVehicleFees(code int64) ([]*VehicleFee, error) {
+	return s.GetVehicleFeesContext(context.Background(), code)
+}
+
+// GetVehicleFeesContext es como GetVehicleFees, pero respeta la cancelación y el plazo
+// del contexto.
+func (s *SRIOnline) GetVehicleFeesContext(ctx context.Context, code int64) ([]*VehicleFee, error) {
+	url := s.vehicleURL("/ConsultaRubros/obtenerPorCodigoVehiculo?codigoVehiculo=%d", code)
+
+	return cached(ctx, s, "GetVehicleFees", url, isEmpty[*VehicleFee])
+}
+
+// GetVehicleDebts obtiene los datos de un vehículo por su placa, CAMV o CPN junto con
+// sus valores pendientes de pago.
+func (s *SRIOnline) GetVehicleDebts(plate string) (*VehicleDebts, error) {
+	return s.GetVehicleDebtsContext(context.Background(), plate)
+}
+
+// GetVehicleDebtsContext es como GetVehicleDebts, pero respeta la cancelación y el
+// plazo del contexto.
+func (s *SRIOnline) GetVehicleDebtsContext(ctx context.Context, plate string) (*VehicleDebts, error) {
+	vehicle, err := s.GetVehicleContext(ctx, plate)
+	if err != nil {
+		return nil, err
+	}
+
+	fees, err := s.GetVehicleFeesContext(ctx, vehicle.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	debts := &VehicleDebts{Vehicle: vehicle, Fees: fees}
+	for _, fee := range fees {
+		if fee != nil {
+			debts.Total += fee.Amount
+		}
+	}
+	debts.Total = math.Round(debts.Total*100) / 100
+
+	return debts, nil
+}
+
+// normalizePlate elimina espacios y guiones de una placa, CAMV o CPN ("PBX-1234" resulta
+// en "PBX1234") y verifica que solo contenga letras y dígitos.
+func normalizePlate(plate string) (string, error) {
+	value := strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(plate))
+
+	if len(value) < 5 || strings.ContainsFunc(value, func(r rune) bool {
+		return (r < 'A' || r > 'Z') && (r < '0' || r > '9')
+	}) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPlate, plate)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,77 @@
+package ws_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pinzlab/sricore/ws"
+)
+
+// newVehicleService registra un vehículo con dos valores pendientes.
+func newVehicleService(t *testing.T) *ws.SRIOnline {
+	service, server := newTestService(t)
+
+	expiry, err := ws.ParseDate("2026-12-31 00:00:00.0")
+	require.NoError(t, err)
+
+	server.AddVehicle(&ws.Vehicle{
+		Code:               1234567,
+		Plate:              "PBX1234",
+		CAMV:               "AB123456",
+		Brand:              "CHEVROLET",
+		Model:              "AVEO",
+		Year:               2015,
+		Displacement:       1600,
+		Class:              "AUTOMOVIL",
+		Service:            "PARTICULAR",
+		LastPaidYear:       2025,
+		RegistrationExpiry: expiry,
+		SaleProhibited:     true,
+	},
+		&ws.VehicleFee{Description: "IMPUESTO A LA PROPIEDAD DE VEHICULOS", Beneficiary: "SRI", FiscalYear: 2026, Amount: 45.1},
+		&ws.VehicleFee{Description: "TASA DE MATRICULACION", Beneficiary: "ANT", FiscalYear: 2026, Amount: 22.2},
+	)
+
+	return service
+}
+
+func TestGetVehicle(t *testing.T) {
+	service := newVehicleService(t)
+
+	vehicle, err := service.GetVehicle("pbx-1234")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1234567), vehicle.Code)
+	assert.Equal(t, "CHEVROLET", vehicle.Brand)
+	assert.Equal(t, 2026, vehicle.RegistrationExpiry.Year())
+	assert.True(t, bool(vehicle.SaleProhibited))
+	assert.True(t, vehicle.LastRegistration.IsZero())
+
+	vehicle, err = service.GetVehicle("AB123456")
+	require.NoError(t, err)
+	assert.Equal(t, "PBX1234", vehicle.Plate)
+
+	_, err = service.GetVehicle("XYZ9999")
+	assert.ErrorIs(t, err, ws.ErrVehicleNotFound)
+
+	for _, plate := range []string{"", "PB", "PBX 1234&x=1", "PBX/1234"} {
+		_, err = service.GetVehicle(plate)
+		assert.ErrorIs(t, err, ws.ErrInvalidPlate, plate)
+	}
+}
+
+func TestGetVehicleDebts(t *testing.T) {
+	service := newVehicleService(t)
+
+	debts, err := service.GetVehicleDebts("PBX1234")
+	require.NoError(t, err)
+	assert.Equal(t, "PBX1234", debts.Vehicle.Plate)
+	require.Len(t, debts.Fees, 2)
+	assert.Equal(t, "ANT", debts.Fees[1].Beneficiary)
+	assert.Equal(t, 67.3, debts.Total)
+
+	fees, err := service.GetVehicleFees(7654321)
+	require.NoError(t, err)
+	assert.Empty(t, fees)
+}
@@ -0,0 +1,48 @@
+package xades
+
+import (
+	"crypto"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+)
+
+const (
+	// NamespaceDS es el espacio de nombres de XML Signature.
+	NamespaceDS = "http://www.w3.org/2000/09/xmldsig#"
+
+	// NamespaceETSI es el espacio de nombres de XAdES 1.3.2 usado por el SRI.
+	NamespaceETSI = "http://uri.etsi.org/01903/v1.3.2#"
+
+	// AlgorithmC14N identifica Canonical XML 1.0 sin comentarios.
+	AlgorithmC14N = "http://www.w3.org/TR/2001/REC-xml-c14n-20010315"
+
+	// AlgorithmEnveloped identifica la transformación de firma envuelta.
+	AlgorithmEnveloped = "http://www.w3.org/2000/09/xmldsig#enveloped-signature"
+
+	// TypeSignedProperties identifica la referencia a las propiedades firmadas de XAdES.
+	TypeSignedProperties = "http://uri.etsi.org/01903#SignedProperties"
+)
+
+// digestMethods relaciona los algoritmos de resumen de XML Signature con su función hash.
+var digestMethods = map[string]crypto.Hash{
+	"http://www.w3.org/2000/09/xmldsig#sha1":        crypto.SHA1,
+	"http://www.w3.org/2001/04/xmlenc#sha256":       crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#sha384": crypto.SHA384,
+	"http://www.w3.org/2001/04/xmlenc#sha512":       crypto.SHA512,
+}
+
+// signatureMethods relaciona los algoritmos de firma RSA con su función hash.
+var signatureMethods = map[string]crypto.Hash{
+	"http://www.w3.org/2000/09/xmldsig#rsa-sha1":        crypto.SHA1,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha256": crypto.SHA256,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha384": crypto.SHA384,
+	"http://www.w3.org/2001/04/xmldsig-more#rsa-sha512": crypto.SHA512,
+}
+
+// digest calcula el resumen de los datos con la función hash indicada.
+func digest(hash crypto.Hash, data []byte) []byte {
+	h := hash.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
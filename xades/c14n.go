@@ -0,0 +1,151 @@
+package xades
+
+import (
+	"encoding/xml"
+	"sort"
+	"strings"
+)
+
+// canonicalize serializa el elemento y su contenido según Canonical XML 1.0 sin
+// comentarios (http://www.w3.org/TR/2001/REC-xml-c14n-20010315).
+//
+// El elemento se trata como ápice del subconjunto, por lo que recibe las declaraciones
+// de espacios de nombres heredadas de sus ancestros. Si exclude no es nil, ese elemento
+// se omite de la salida (transformación de firma envuelta).
+func canonicalize(n, exclude *node) []byte {
+	var b strings.Builder
+	writeCanonical(&b, n, exclude, map[string]string{})
+	return []byte(b.String())
+}
+
+// inScope devuelve las declaraciones de espacios de nombres vigentes en el elemento.
+func (n *node) inScope() map[string]string {
+	var chain []*node
+	for e := n; e != nil; e = e.parent {
+		chain = append(chain, e)
+	}
+
+	scope := map[string]string{}
+	for i := len(chain) - 1; i >= 0; i-- {
+		for prefix, uri := range chain[i].ns {
+			scope[prefix] = uri
+		}
+	}
+
+	return scope
+}
+
+// writeCanonical escribe el elemento en forma canónica. rendered contiene los espacios
+// de nombres ya emitidos por el ancestro más cercano en la salida.
+func writeCanonical(b *strings.Builder, n, exclude *node, rendered map[string]string) {
+	scope := n.inScope()
+	current := make(map[string]string, len(scope))
+
+	var prefixes []string
+	for prefix, uri := range scope {
+		current[prefix] = uri
+		if prefix == "xml" {
+			continue
+		}
+
+		parent, ok := rendered[prefix]
+		if prefix == "" {
+			// Un espacio de nombres por defecto vacío solo se emite para anular uno heredado
+			if uri != parent {
+				prefixes = append(prefixes, prefix)
+			}
+			continue
+		}
+
+		if !ok || parent != uri {
+			prefixes = append(prefixes, prefix)
+		}
+	}
+	sort.Strings(prefixes)
+
+	b.WriteByte('<')
+	b.WriteString(qualified(n.name))
+
+	for _, prefix := range prefixes {
+		if prefix == "" {
+			b.WriteString(` xmlns="`)
+		} else {
+			b.WriteString(` xmlns:` + prefix + `="`)
+		}
+		b.WriteString(escapeAttr(scope[prefix]))
+		b.WriteByte('"')
+	}
+
+	attrs := make([]xml.Attr, len(n.attrs))
+	copy(attrs, n.attrs)
+	sort.SliceStable(attrs, func(i, j int) bool {
+		ni, nj := attrNamespace(n, attrs[i]), attrNamespace(n, attrs[j])
+		if ni != nj {
+			return ni < nj
+		}
+		return attrs[i].Name.Local < attrs[j].Name.Local
+	})
+
+	for _, attr := range attrs {
+		b.WriteByte(' ')
+		b.WriteString(qualified(attr.Name))
+		b.WriteString(`="`)
+		b.WriteString(escapeAttr(attr.Value))
+		b.WriteByte('"')
+	}
+	b.WriteByte('>')
+
+	for _, c := range n.children {
+		switch child := c.(type) {
+		case *node:
+			if child != exclude {
+				writeCanonical(b, child, exclude, current)
+			}
+		case xml.CharData:
+			b.WriteString(escapeText(string(child)))
+		}
+	}
+
+	b.WriteString("</" + qualified(n.name) + ">")
+}
+
+// attrNamespace devuelve el URI del espacio de nombres de un atributo.
+// Los atributos sin prefijo no pertenecen a ningún espacio de nombres.
+func attrNamespace(n *node, attr xml.Attr) string {
+	if attr.Name.Space == "" {
+		return ""
+	}
+
+	return n.lookup(attr.Name.Space)
+}
+
+// qualified devuelve el nombre con su prefijo, tal como aparece en el documento.
+func qualified(name xml.Name) string {
+	if name.Space == "" {
+		return name.Local
+	}
+
+	return name.Space + ":" + name.Local
+}
+
+// escapeText aplica el escape de Canonical XML para nodos de texto.
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		">", "&gt;",
+		"\r", "&#xD;",
+	).Replace(s)
+}
+
+// escapeAttr aplica el escape de Canonical XML para valores de atributos.
+func escapeAttr(s string) string {
+	return strings.NewReplacer(
+		"&", "&amp;",
+		"<", "&lt;",
+		`"`, "&quot;",
+		"\t", "&#x9;",
+		"\n", "&#xA;",
+		"\r", "&#xD;",
+	).Replace(s)
+}
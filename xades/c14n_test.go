@@ -0,0 +1,54 @@
+package xades
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		doc      string
+		id       string
+		expected string
+	}{
+		{
+			name:     "empty elements and declaration",
+			doc:      `<?xml version="1.0" encoding="UTF-8"?><factura id="comprobante" version="1.1.0"><infoTributaria/></factura>`,
+			expected: `<factura id="comprobante" version="1.1.0"><infoTributaria></infoTributaria></factura>`,
+		},
+		{
+			name:     "attribute order and escaping",
+			doc:      `<a z="1" b='x"y' a="&lt;&amp;">&quot;texto&apos; &gt; &#13;</a>`,
+			expected: `<a a="&lt;&amp;" b="x&quot;y" z="1">"texto' &gt; &#xD;</a>`,
+		},
+		{
+			name:     "namespaces inherited by subset apex",
+			doc:      `<ds:Signature xmlns:ds="http://www.w3.org/2000/09/xmldsig#" xmlns:etsi="http://uri.etsi.org/01903/v1.3.2#"><ds:Object><etsi:SignedProperties Id="p"><etsi:SigningTime>2025</etsi:SigningTime></etsi:SignedProperties></ds:Object></ds:Signature>`,
+			id:       "p",
+			expected: `<etsi:SignedProperties xmlns:ds="http://www.w3.org/2000/09/xmldsig#" xmlns:etsi="http://uri.etsi.org/01903/v1.3.2#" Id="p"><etsi:SigningTime>2025</etsi:SigningTime></etsi:SignedProperties>`,
+		},
+		{
+			name:     "redundant declarations removed",
+			doc:      `<a xmlns="urn:a" xmlns:b="urn:b"><b:c xmlns:b="urn:b" xmlns="urn:a"><d xmlns=""></d></b:c></a>`,
+			expected: `<a xmlns="urn:a" xmlns:b="urn:b"><b:c><d xmlns=""></d></b:c></a>`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root, err := parse([]byte(test.doc))
+			require.NoError(t, err)
+
+			target := root
+			if test.id != "" {
+				target = root.findID(test.id)
+				require.NotNil(t, target)
+			}
+
+			assert.Equal(t, test.expected, string(canonicalize(target, nil)))
+		})
+	}
+}
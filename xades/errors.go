@@ -0,0 +1,16 @@
+package xades
+
+import "errors"
+
+var (
+	ErrInvalidDocument     = errors.New("El documento XML no es válido")
+	ErrSignatureNotFound   = errors.New("El documento no contiene una firma electrónica")
+	ErrUnsupportedMethod   = errors.New("Algoritmo de firma no soportado")
+	ErrReferenceNotFound   = errors.New("No se encontró el elemento referenciado por la firma")
+	ErrDigestMismatch      = errors.New("El resumen del contenido firmado no coincide")
+	ErrInvalidSignature    = errors.New("El valor de la firma no es válido")
+	ErrCertificateNotFound = errors.New("La firma no incluye el certificado del firmante")
+	ErrSigningCertificate  = errors.New("El certificado de la firma no coincide con el declarado en las propiedades firmadas")
+	ErrInvalidSigningTime  = errors.New("La fecha de firma no es válida")
+	ErrRemoteSigner        = errors.New("El servicio de firma remoto no pudo firmar el documento")
+)
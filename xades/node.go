@@ -0,0 +1,179 @@
+package xades
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// node es un elemento XML que conserva los prefijos y declaraciones de espacios de
+// nombres tal como aparecen en el documento, necesario para la canonicalización.
+type node struct {
+	// Prefijo y nombre local del elemento.
+	name xml.Name
+
+	// Atributos del elemento sin las declaraciones de espacios de nombres.
+	attrs []xml.Attr
+
+	// Declaraciones de espacios de nombres del elemento (prefijo -> URI).
+	// El espacio de nombres por defecto se registra con el prefijo vacío.
+	ns map[string]string
+
+	// Nodo padre; nil para la raíz.
+	parent *node
+
+	// Contenido del elemento: *node o xml.CharData.
+	children []any
+}
+
+// parse construye el árbol de elementos de un documento XML.
+func parse(doc []byte) (*node, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(doc))
+	var root, current *node
+
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidDocument, err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			element := &node{name: t.Name, ns: map[string]string{}, parent: current}
+			for _, attr := range t.Attr {
+				switch {
+				case attr.Name.Space == "" && attr.Name.Local == "xmlns":
+					element.ns[""] = attr.Value
+				case attr.Name.Space == "xmlns":
+					element.ns[attr.Name.Local] = attr.Value
+				default:
+					// Normalización de atributos: los espacios literales se convierten en un espacio
+					attr.Value = strings.NewReplacer("\t", " ", "\n", " ", "\r", " ").Replace(attr.Value)
+					element.attrs = append(element.attrs, attr)
+				}
+			}
+
+			if current == nil {
+				if root != nil {
+					return nil, ErrInvalidDocument
+				}
+				root = element
+			} else {
+				current.children = append(current.children, element)
+			}
+			current = element
+
+		case xml.EndElement:
+			if current == nil {
+				return nil, ErrInvalidDocument
+			}
+			current = current.parent
+
+		case xml.CharData:
+			if current != nil {
+				current.children = append(current.children, t.Copy())
+			}
+		}
+	}
+
+	if root == nil || current != nil {
+		return nil, ErrInvalidDocument
+	}
+
+	return root, nil
+}
+
+// lookup resuelve el URI asociado a un prefijo en el contexto del elemento.
+func (n *node) lookup(prefix string) string {
+	for e := n; e != nil; e = e.parent {
+		if uri, ok := e.ns[prefix]; ok {
+			return uri
+		}
+	}
+
+	if prefix == "xml" {
+		return "http://www.w3.org/XML/1998/namespace"
+	}
+
+	return ""
+}
+
+// attr devuelve el valor del atributo con el nombre local indicado.
+func (n *node) attr(local string) string {
+	for _, attr := range n.attrs {
+		if attr.Name.Local == local {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+// child devuelve el primer hijo directo con el nombre local indicado.
+func (n *node) child(local string) *node {
+	for _, c := range n.children {
+		if e, ok := c.(*node); ok && e.name.Local == local {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// childrenNamed devuelve los hijos directos con el nombre local indicado.
+func (n *node) childrenNamed(local string) []*node {
+	var result []*node
+	for _, c := range n.children {
+		if e, ok := c.(*node); ok && e.name.Local == local {
+			result = append(result, e)
+		}
+	}
+
+	return result
+}
+
+// find busca en profundidad el primer descendiente (o el propio elemento) que cumple match.
+func (n *node) find(match func(*node) bool) *node {
+	if match(n) {
+		return n
+	}
+
+	for _, c := range n.children {
+		if e, ok := c.(*node); ok {
+			if found := e.find(match); found != nil {
+				return found
+			}
+		}
+	}
+
+	return nil
+}
+
+// findNamed busca el primer descendiente con el nombre local indicado.
+func (n *node) findNamed(local string) *node {
+	return n.find(func(e *node) bool { return e.name.Local == local })
+}
+
+// findID busca el elemento cuyo atributo Id (o id) coincide con el valor indicado.
+func (n *node) findID(value string) *node {
+	return n.find(func(e *node) bool {
+		return e.attr("Id") == value || e.attr("id") == value
+	})
+}
+
+// text devuelve el contenido textual del elemento sin espacios en los extremos.
+func (n *node) text() string {
+	var b strings.Builder
+	for _, c := range n.children {
+		if data, ok := c.(xml.CharData); ok {
+			b.Write(data)
+		}
+	}
+
+	return strings.TrimSpace(b.String())
+}
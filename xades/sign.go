@@ -0,0 +1,201 @@
+package xades
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+const (
+	// sha1Method es el algoritmo de resumen exigido por el SRI.
+	sha1Method = "http://www.w3.org/2000/09/xmldsig#sha1"
+
+	// rsaSHA1Method es el algoritmo de firma exigido por el SRI.
+	rsaSHA1Method = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
+)
+
+// Sign firma un comprobante electrónico con una firma XAdES-BES envuelta, según la
+// ficha técnica de comprobantes electrónicos del SRI (RSA-SHA1, Canonical XML 1.0).
+//
+// El elemento raíz del comprobante debe tener el atributo id="comprobante".
+// La firma se agrega como último hijo del elemento raíz.
+func Sign(doc []byte, signer Signer) ([]byte, error) {
+	return SignContext(context.Background(), doc, signer)
+}
+
+// SignContext firma un comprobante como Sign. El contexto se entrega al firmante, de
+// modo que su cancelación o plazo interrumpen la firma en un servicio remoto.
+func SignContext(ctx context.Context, doc []byte, signer Signer) ([]byte, error) {
+	return signAt(ctx, doc, signer, time.Now())
+}
+
+// signAt firma el comprobante registrando la fecha de firma indicada.
+func signAt(ctx context.Context, doc []byte, signer Signer, signingTime time.Time) ([]byte, error) {
+	root, err := parse(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	certificate := signer.Certificate()
+	if certificate == nil {
+		return nil, ErrCertificateNotFound
+	}
+
+	key, ok := certificate.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedMethod, certificate.PublicKey)
+	}
+
+	voucherID := root.attr("id")
+	if voucherID == "" {
+		voucherID = root.attr("Id")
+	}
+	if voucherID == "" {
+		return nil, fmt.Errorf("%w: comprobante", ErrReferenceNotFound)
+	}
+
+	end := bytes.LastIndex(doc, []byte("</"+qualified(root.name)))
+	if end < 0 {
+		return nil, ErrInvalidDocument
+	}
+	wrap := func(signature string) []byte {
+		result := make([]byte, 0, len(doc)+len(signature))
+		result = append(result, doc[:end]...)
+		result = append(result, signature...)
+		return append(result, doc[end:]...)
+	}
+
+	ids := newSignatureIDs()
+	open := `<ds:Signature xmlns:ds="` + NamespaceDS + `" xmlns:etsi="` + NamespaceETSI + `" Id="` + ids.signature + `">`
+	closing := `</ds:Signature>`
+
+	keyInfo := `<ds:KeyInfo Id="` + ids.certificate + `">` +
+		`<ds:X509Data><ds:X509Certificate>` + encode(certificate.Raw) + `</ds:X509Certificate></ds:X509Data>` +
+		`<ds:KeyValue><ds:RSAKeyValue>` +
+		`<ds:Modulus>` + encode(key.N.Bytes()) + `</ds:Modulus>` +
+		`<ds:Exponent>` + encode(big.NewInt(int64(key.E)).Bytes()) + `</ds:Exponent>` +
+		`</ds:RSAKeyValue></ds:KeyValue>` +
+		`</ds:KeyInfo>`
+
+	object := `<ds:Object Id="` + ids.signature + `-Object` + ids.object + `">` +
+		`<etsi:QualifyingProperties Target="#` + ids.signature + `">` +
+		`<etsi:SignedProperties Id="` + ids.signedProperties + `">` +
+		`<etsi:SignedSignatureProperties>` +
+		`<etsi:SigningTime>` + signingTime.Format(time.RFC3339) + `</etsi:SigningTime>` +
+		`<etsi:SigningCertificate><etsi:Cert>` +
+		`<etsi:CertDigest><ds:DigestMethod Algorithm="` + sha1Method + `"></ds:DigestMethod>` +
+		`<ds:DigestValue>` + encode(digest(crypto.SHA1, certificate.Raw)) + `</ds:DigestValue></etsi:CertDigest>` +
+		`<etsi:IssuerSerial>` +
+		`<ds:X509IssuerName>` + escapeText(certificate.Issuer.String()) + `</ds:X509IssuerName>` +
+		`<ds:X509SerialNumber>` + certificate.SerialNumber.String() + `</ds:X509SerialNumber>` +
+		`</etsi:IssuerSerial>` +
+		`</etsi:Cert></etsi:SigningCertificate>` +
+		`</etsi:SignedSignatureProperties>` +
+		`<etsi:SignedDataObjectProperties>` +
+		`<etsi:DataObjectFormat ObjectReference="#` + ids.reference + `">` +
+		`<etsi:Description>contenido comprobante</etsi:Description>` +
+		`<etsi:MimeType>text/xml</etsi:MimeType>` +
+		`</etsi:DataObjectFormat>` +
+		`</etsi:SignedDataObjectProperties>` +
+		`</etsi:SignedProperties>` +
+		`</etsi:QualifyingProperties>` +
+		`</ds:Object>`
+
+	// Los resúmenes de KeyInfo y SignedProperties dependen de los espacios de nombres
+	// heredados de ds:Signature, por lo que se calculan sobre el documento ensamblado
+	assembled, err := parse(wrap(open + keyInfo + object + closing))
+	if err != nil {
+		return nil, err
+	}
+
+	signedInfo := `<ds:SignedInfo Id="` + ids.signedInfo + `">` +
+		`<ds:CanonicalizationMethod Algorithm="` + AlgorithmC14N + `"></ds:CanonicalizationMethod>` +
+		`<ds:SignatureMethod Algorithm="` + rsaSHA1Method + `"></ds:SignatureMethod>` +
+		reference(ids.signedPropertiesRef, TypeSignedProperties, "#"+ids.signedProperties, "",
+			digest(crypto.SHA1, canonicalize(assembled.findID(ids.signedProperties), nil))) +
+		reference("", "", "#"+ids.certificate, "",
+			digest(crypto.SHA1, canonicalize(assembled.findID(ids.certificate), nil))) +
+		reference(ids.reference, "", "#"+voucherID, AlgorithmEnveloped,
+			digest(crypto.SHA1, canonicalize(root, nil))) +
+		`</ds:SignedInfo>`
+
+	assembled, err = parse(wrap(open + signedInfo + keyInfo + object + closing))
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := signer.Sign(ctx, digest(crypto.SHA1, canonicalize(assembled.findNamed("SignedInfo"), nil)), crypto.SHA1)
+	if err != nil {
+		return nil, err
+	}
+
+	signatureValue := `<ds:SignatureValue Id="` + ids.signatureValue + `">` + encode(value) + `</ds:SignatureValue>`
+
+	return wrap(open + signedInfo + signatureValue + keyInfo + object + closing), nil
+}
+
+// reference construye un elemento ds:Reference con resumen SHA-1.
+func reference(id, kind, uri, transform string, value []byte) string {
+	var b strings.Builder
+
+	b.WriteString(`<ds:Reference`)
+	if id != "" {
+		b.WriteString(` Id="` + id + `"`)
+	}
+	if kind != "" {
+		b.WriteString(` Type="` + kind + `"`)
+	}
+	b.WriteString(` URI="` + uri + `">`)
+
+	if transform != "" {
+		b.WriteString(`<ds:Transforms><ds:Transform Algorithm="` + transform + `"></ds:Transform></ds:Transforms>`)
+	}
+
+	b.WriteString(`<ds:DigestMethod Algorithm="` + sha1Method + `"></ds:DigestMethod>`)
+	b.WriteString(`<ds:DigestValue>` + encode(value) + `</ds:DigestValue>`)
+	b.WriteString(`</ds:Reference>`)
+
+	return b.String()
+}
+
+// signatureIDs contiene los identificadores de los elementos de una firma.
+type signatureIDs struct {
+	signature           string
+	signedInfo          string
+	signedProperties    string
+	signedPropertiesRef string
+	certificate         string
+	reference           string
+	signatureValue      string
+	object              string
+}
+
+// newSignatureIDs genera identificadores aleatorios para los elementos de la firma,
+// siguiendo la nomenclatura usada en los ejemplos del SRI.
+func newSignatureIDs() signatureIDs {
+	n := func() string { return fmt.Sprint(rand.IntN(900000) + 100000) }
+	signature := "Signature" + n()
+
+	return signatureIDs{
+		signature:           signature,
+		signedInfo:          "Signature-SignedInfo" + n(),
+		signedProperties:    signature + "-SignedProperties" + n(),
+		signedPropertiesRef: "SignedPropertiesID" + n(),
+		certificate:         "Certificate" + n(),
+		reference:           "Reference-ID-" + n(),
+		signatureValue:      "SignatureValue" + n(),
+		object:              n(),
+	}
+}
+
+// encode codifica en base64 estándar.
+func encode(data []byte) string {
+	return base64.StdEncoding.EncodeToString(data)
+}
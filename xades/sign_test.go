@@ -0,0 +1,72 @@
+package xades
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/cert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSign(t *testing.T) {
+	now := time.Now()
+	_, leaf := newTestPKI(t, now.Add(-24*time.Hour), now.Add(24*time.Hour))
+
+	signer, err := NewFileSigner(&cert.Certificate{Leaf: leaf, PrivateKey: testKey})
+	require.NoError(t, err)
+
+	doc, err := Sign([]byte(testVoucher), signer)
+	require.NoError(t, err)
+
+	root, err := parse(doc)
+	require.NoError(t, err)
+
+	// La firma se envuelve como último hijo del comprobante
+	signature := root.children[len(root.children)-1].(*node)
+	assert.Equal(t, "Signature", signature.name.Local)
+	assert.Equal(t, NamespaceDS, signature.lookup("ds"))
+	assert.Len(t, signature.child("SignedInfo").childrenNamed("Reference"), 3)
+	assert.True(t, strings.HasSuffix(string(doc), "</factura>"))
+
+	_, err = Sign([]byte(`<factura><infoTributaria/></factura>`), signer)
+	assert.ErrorIs(t, err, ErrReferenceNotFound)
+}
+
+// TestSign_Digests compara los resúmenes de Sign con los calculados por xmllint y OpenSSL
+// en testdata/generar.sh para la misma factura y el mismo certificado.
+func TestSign_Digests(t *testing.T) {
+	const (
+		voucherDigest = "BxGj3TBtfGs4Bkwiea5Ubl5EOQY="
+		certDigest    = "ii8iaK82wsBattVpkuBqiICys4k="
+	)
+
+	doc, err := os.ReadFile("testdata/factura.xml")
+	require.NoError(t, err)
+
+	certificate, err := os.ReadFile("testdata/firmante.pem")
+	require.NoError(t, err)
+	key, err := os.ReadFile("testdata/firmante.key")
+	require.NoError(t, err)
+
+	c, err := cert.LoadPEM(append(certificate, key...))
+	require.NoError(t, err)
+	signer, err := NewFileSigner(c)
+	require.NoError(t, err)
+
+	signed, err := signAt(context.Background(), doc, signer, time.Date(2025, 1, 15, 10, 30, 0, 0, time.FixedZone("ECT", -5*60*60)))
+	require.NoError(t, err)
+
+	root, err := parse(signed)
+	require.NoError(t, err)
+
+	digests := map[string]string{}
+	for _, reference := range root.findNamed("SignedInfo").childrenNamed("Reference") {
+		digests[reference.attr("URI")] = reference.child("DigestValue").text()
+	}
+	assert.Equal(t, voucherDigest, digests["#comprobante"])
+	assert.Equal(t, certDigest, root.findNamed("CertDigest").child("DigestValue").text())
+}
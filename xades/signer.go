@@ -0,0 +1,167 @@
+package xades
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/pinzlab/sricore/cert"
+)
+
+// Signer firma resúmenes con la clave privada asociada a un certificado, sin que el
+// código de firma XAdES necesite acceder a la clave.
+type Signer interface {
+	// Certificate devuelve el certificado del firmante.
+	Certificate() *x509.Certificate
+
+	// Sign firma un resumen calculado con la función hash indicada y devuelve el valor
+	// de la firma PKCS#1 v1.5. La cancelación o el plazo del contexto deben interrumpir
+	// la firma.
+	Sign(ctx context.Context, digest []byte, hash crypto.Hash) ([]byte, error)
+}
+
+// FileSigner firma con una clave privada cargada en memoria, normalmente desde un
+// archivo PKCS#12.
+type FileSigner struct {
+	certificate *cert.Certificate
+}
+
+// NewFileSigner crea un firmante a partir de un certificado con clave privada.
+func NewFileSigner(c *cert.Certificate) (*FileSigner, error) {
+	if c == nil || c.PrivateKey == nil {
+		return nil, cert.ErrNoPrivateKey
+	}
+
+	return &FileSigner{certificate: c}, nil
+}
+
+// LoadFileSigner crea un firmante a partir de un archivo PKCS#12 (.p12, .pfx) o PEM.
+func LoadFileSigner(path, password string) (*FileSigner, error) {
+	c, err := cert.LoadFile(path, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewFileSigner(c)
+}
+
+// Certificate devuelve el certificado del firmante.
+func (s *FileSigner) Certificate() *x509.Certificate {
+	return s.certificate.Leaf
+}
+
+// Sign firma el resumen con la clave privada del certificado.
+func (s *FileSigner) Sign(ctx context.Context, digest []byte, hash crypto.Hash) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return s.certificate.PrivateKey.Sign(rand.Reader, digest, hash)
+}
+
+// RemoteSigner delega la firma a un servicio HTTP, como un HSM de red o un servicio
+// de firma centralizado, de modo que la clave privada nunca llega al proceso.
+//
+// El servicio recibe una solicitud POST con el cuerpo JSON
+//
+//	{"hash": "SHA-1", "digest": "<resumen en base64>"}
+//
+// y debe responder con estado 200 y el cuerpo
+//
+//	{"signature": "<firma PKCS#1 v1.5 en base64>"}
+type RemoteSigner struct {
+	url         string
+	certificate *x509.Certificate
+	client      *http.Client
+	header      http.Header
+}
+
+// remoteRequest es el cuerpo enviado al servicio de firma remoto.
+type remoteRequest struct {
+	Hash   string `json:"hash"`
+	Digest string `json:"digest"`
+}
+
+// remoteResponse es el cuerpo devuelto por el servicio de firma remoto.
+type remoteResponse struct {
+	Signature string `json:"signature"`
+}
+
+// DefaultRemoteSignerTimeout es el tiempo máximo de cada solicitud del cliente que usa
+// NewRemoteSigner cuando no recibe uno.
+const DefaultRemoteSignerTimeout = 30 * time.Second
+
+// NewRemoteSigner crea un firmante que usa el servicio publicado en url. El certificado
+// debe corresponder a la clave que custodia el servicio. Si client es nil se usa un
+// cliente con un tiempo máximo de DefaultRemoteSignerTimeout, para que un servicio que
+// no responde no bloquee la firma indefinidamente.
+func NewRemoteSigner(url string, certificate *x509.Certificate, client *http.Client) *RemoteSigner {
+	if client == nil {
+		client = &http.Client{Timeout: DefaultRemoteSignerTimeout}
+	}
+
+	return &RemoteSigner{url: url, certificate: certificate, client: client, header: http.Header{}}
+}
+
+// SetHeader agrega una cabecera a cada solicitud, por ejemplo para autenticación.
+func (s *RemoteSigner) SetHeader(key, value string) {
+	s.header.Set(key, value)
+}
+
+// Certificate devuelve el certificado del firmante.
+func (s *RemoteSigner) Certificate() *x509.Certificate {
+	return s.certificate
+}
+
+// Sign envía el resumen al servicio remoto y devuelve la firma recibida.
+func (s *RemoteSigner) Sign(ctx context.Context, digest []byte, hash crypto.Hash) ([]byte, error) {
+	body, err := json.Marshal(remoteRequest{
+		Hash:   hash.String(),
+		Digest: base64.StdEncoding.EncodeToString(digest),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRemoteSigner, err)
+	}
+	req.Header = s.header.Clone()
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrRemoteSigner, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: estado %d", ErrRemoteSigner, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRemoteSigner, err)
+	}
+
+	var result remoteResponse
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrRemoteSigner, err)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(result.Signature)
+	if err != nil || len(signature) == 0 {
+		return nil, fmt.Errorf("%w: firma vacía o inválida", ErrRemoteSigner)
+	}
+
+	return signature, nil
+}
@@ -0,0 +1,92 @@
+package xades
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/cert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewFileSigner(t *testing.T) {
+	_, err := NewFileSigner(&cert.Certificate{})
+	assert.ErrorIs(t, err, cert.ErrNoPrivateKey)
+}
+
+func TestRemoteSigner(t *testing.T) {
+	now := time.Now()
+	root, leaf := newTestPKI(t, now.Add(-24*time.Hour), now.Add(24*time.Hour))
+
+	// Stand-in for a remote signing service that keeps the private key
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var req remoteRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "SHA-1", req.Hash)
+
+		digest, err := base64.StdEncoding.DecodeString(req.Digest)
+		require.NoError(t, err)
+
+		signature, err := rsa.SignPKCS1v15(rand.Reader, testKey, crypto.SHA1, digest)
+		require.NoError(t, err)
+
+		_ = json.NewEncoder(w).Encode(remoteResponse{Signature: base64.StdEncoding.EncodeToString(signature)})
+	}))
+	defer server.Close()
+
+	signer := NewRemoteSigner(server.URL, leaf, server.Client())
+
+	_, err := Sign([]byte(testVoucher), signer)
+	assert.ErrorIs(t, err, ErrRemoteSigner)
+
+	signer.SetHeader("Authorization", "Bearer token")
+
+	doc, err := Sign([]byte(testVoucher), signer)
+	require.NoError(t, err)
+
+	store := cert.NewTrustStore()
+	store.AddCertificate(root)
+
+	signature, err := Verify(doc, VerifyOptions{TrustStore: store})
+	require.NoError(t, err)
+	assert.Equal(t, leaf.Raw, signature.Certificate.Raw)
+}
+
+func TestRemoteSigner_Context(t *testing.T) {
+	_, leaf := newTestPKI(t, time.Now().Add(-24*time.Hour), time.Now().Add(24*time.Hour))
+
+	// A signing service that never answers.
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := SignContext(ctx, []byte(testVoucher), NewRemoteSigner(server.URL, leaf, nil))
+	assert.ErrorIs(t, err, ErrRemoteSigner)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	assert.Equal(t, DefaultRemoteSignerTimeout, NewRemoteSigner(server.URL, leaf, nil).client.Timeout)
+}
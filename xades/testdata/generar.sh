@@ -0,0 +1,81 @@
+#!/bin/sh
+# Genera factura-firmada.xml con una firma XAdES-BES construida solo con herramientas
+# externas (xmllint para Canonical XML 1.0 y OpenSSL para los resúmenes y la firma),
+# de modo que las pruebas de xades no comparen Sign y Verify solo entre sí.
+#
+# Uso: sh generar.sh (desde el directorio testdata)
+set -eu
+
+DS=http://www.w3.org/2000/09/xmldsig#
+ETSI=http://uri.etsi.org/01903/v1.3.2#
+NS="xmlns:ds=\"$DS\" xmlns:etsi=\"$ETSI\""
+SIGNING_TIME=2025-01-15T10:30:00-05:00
+
+if [ ! -f firmante.key ]; then
+	work=$(mktemp -d)
+	trap 'rm -rf "$work"' EXIT
+	touch "$work/index.txt"
+	echo 1000 >"$work/serial"
+	cat >"$work/ca.cnf" <<-CNF
+	[ca]
+	default_ca = ac
+	[ac]
+	database = $work/index.txt
+	serial = $work/serial
+	new_certs_dir = $work
+	default_md = sha256
+	policy = libre
+	unique_subject = no
+	[libre]
+	countryName = optional
+	organizationName = optional
+	commonName = optional
+	serialNumber = optional
+	[raiz]
+	basicConstraints = critical,CA:TRUE
+	keyUsage = critical,keyCertSign,cRLSign
+	[firmante]
+	keyUsage = critical,digitalSignature,nonRepudiation
+	CNF
+	openssl req -new -newkey rsa:2048 -nodes -keyout "$work/ac.key" -out "$work/ac.csr" \
+		-subj "/C=EC/O=AC PRUEBAS SRICORE/CN=AC RAIZ PRUEBAS SRICORE"
+	openssl ca -batch -config "$work/ca.cnf" -selfsign -keyfile "$work/ac.key" -in "$work/ac.csr" \
+		-out ac.pem -notext -extensions raiz \
+		-startdate 20240101000000Z -enddate 20491231235959Z
+	openssl req -new -newkey rsa:2048 -nodes -keyout firmante.key -out "$work/firmante.csr" \
+		-subj "/C=EC/O=COMERCIAL PENA Y ASOCIADOS S.A./CN=JUAN PEREZ/serialNumber=0601234560"
+	openssl ca -batch -config "$work/ca.cnf" -cert ac.pem -keyfile "$work/ac.key" -in "$work/firmante.csr" \
+		-out firmante.pem -notext -extensions firmante \
+		-startdate 20240101000000Z -enddate 20481231235959Z
+fi
+
+digest() {
+	openssl dgst -sha1 -binary | openssl base64 -A
+}
+
+c14n() {
+	xmllint --c14n - </dev/stdin
+}
+
+certificate=$(openssl x509 -in firmante.pem -outform DER | openssl base64 -A)
+cert_digest=$(openssl x509 -in firmante.pem -outform DER | digest)
+
+# Las referencias a KeyInfo y SignedProperties se canonicalizan como subárboles, con los
+# espacios de nombres heredados de ds:Signature declarados en el propio elemento.
+key_info="<ds:KeyInfo Id=\"Certificate1\"><ds:X509Data><ds:X509Certificate>$certificate</ds:X509Certificate></ds:X509Data></ds:KeyInfo>"
+properties="<etsi:SignedProperties Id=\"Signature1-SignedProperties\"><etsi:SignedSignatureProperties><etsi:SigningTime>$SIGNING_TIME</etsi:SigningTime><etsi:SigningCertificate><etsi:Cert><etsi:CertDigest><ds:DigestMethod Algorithm=\"${DS}sha1\"></ds:DigestMethod><ds:DigestValue>$cert_digest</ds:DigestValue></etsi:CertDigest></etsi:Cert></etsi:SigningCertificate></etsi:SignedSignatureProperties></etsi:SignedProperties>"
+
+voucher_digest=$(c14n <factura.xml | digest)
+key_info_digest=$(echo "$key_info" | sed "s|<ds:KeyInfo |<ds:KeyInfo $NS |" | c14n | digest)
+properties_digest=$(echo "$properties" | sed "s|<etsi:SignedProperties |<etsi:SignedProperties $NS |" | c14n | digest)
+
+signed_info="<ds:SignedInfo><ds:CanonicalizationMethod Algorithm=\"http://www.w3.org/TR/2001/REC-xml-c14n-20010315\"></ds:CanonicalizationMethod><ds:SignatureMethod Algorithm=\"${DS}rsa-sha1\"></ds:SignatureMethod><ds:Reference Type=\"http://uri.etsi.org/01903#SignedProperties\" URI=\"#Signature1-SignedProperties\"><ds:DigestMethod Algorithm=\"${DS}sha1\"></ds:DigestMethod><ds:DigestValue>$properties_digest</ds:DigestValue></ds:Reference><ds:Reference URI=\"#Certificate1\"><ds:DigestMethod Algorithm=\"${DS}sha1\"></ds:DigestMethod><ds:DigestValue>$key_info_digest</ds:DigestValue></ds:Reference><ds:Reference URI=\"#comprobante\"><ds:Transforms><ds:Transform Algorithm=\"${DS}enveloped-signature\"></ds:Transform></ds:Transforms><ds:DigestMethod Algorithm=\"${DS}sha1\"></ds:DigestMethod><ds:DigestValue>$voucher_digest</ds:DigestValue></ds:Reference></ds:SignedInfo>"
+
+signature_value=$(echo "$signed_info" | sed "s|<ds:SignedInfo>|<ds:SignedInfo $NS>|" | c14n |
+	openssl dgst -sha1 -sign firmante.key | openssl base64 -A)
+
+signature="<ds:Signature $NS Id=\"Signature1\">$signed_info<ds:SignatureValue>$signature_value</ds:SignatureValue>$key_info<ds:Object><etsi:QualifyingProperties Target=\"#Signature1\">$properties</etsi:QualifyingProperties></ds:Object></ds:Signature>"
+
+sed "s|</factura>|$signature</factura>|" factura.xml >factura-firmada.xml
+echo "comprobante: $voucher_digest"
+echo "certificado: $cert_digest"
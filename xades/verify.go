@@ -0,0 +1,283 @@
+package xades
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pinzlab/sricore/cert"
+)
+
+// Signature contiene la información de una firma XAdES-BES verificada.
+type Signature struct {
+	// Certificate: Certificado del firmante.
+	Certificate *x509.Certificate
+
+	// Intermediates: Certificados adicionales incluidos en KeyInfo.
+	Intermediates []*x509.Certificate
+
+	// SigningTime: Fecha de firma registrada en las propiedades firmadas.
+	SigningTime time.Time
+
+	// Chain: Cadena de certificación validada, desde el firmante hasta la raíz.
+	Chain []*x509.Certificate
+}
+
+// VerifyOptions configura la verificación de una firma.
+type VerifyOptions struct {
+	// TrustStore: Almacén de raíces de confianza. Si es nil se usa cert.DefaultTrustStore.
+	TrustStore *cert.TrustStore
+
+	// CRLs: Listas de revocación descargadas previamente. Si no es nil, se comprueba que
+	// el certificado no estuviera revocado en la fecha de firma; la revocación se reporta
+	// como un error *cert.Revocation con el motivo y la fecha.
+	CRLs *cert.CRLStore
+}
+
+// Verify verifica la firma XAdES-BES envuelta en un comprobante electrónico.
+//
+// Comprueba los resúmenes de cada referencia, el valor de la firma, que el certificado
+// coincida con el declarado en las propiedades firmadas, que se encadene a una raíz
+// de confianza en la fecha de firma registrada en SignedProperties y, si se indican
+// listas de revocación, que no estuviera revocado en esa fecha.
+func Verify(doc []byte, opts VerifyOptions) (*Signature, error) {
+	root, err := parse(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	signature := root.find(func(e *node) bool {
+		return e.name.Local == "Signature" && e.lookup(e.name.Space) == NamespaceDS
+	})
+	if signature == nil {
+		return nil, ErrSignatureNotFound
+	}
+
+	signedInfo := signature.child("SignedInfo")
+	if signedInfo == nil {
+		return nil, ErrSignatureNotFound
+	}
+
+	if method := algorithm(signedInfo.child("CanonicalizationMethod")); method != AlgorithmC14N {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+	}
+
+	hash, ok := signatureMethods[algorithm(signedInfo.child("SignatureMethod"))]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, algorithm(signedInfo.child("SignatureMethod")))
+	}
+
+	properties := signature.find(func(e *node) bool { return e.name.Local == "SignedProperties" })
+	if properties == nil {
+		return nil, ErrInvalidSigningTime
+	}
+
+	covered := map[*node]bool{}
+	for _, reference := range signedInfo.childrenNamed("Reference") {
+		target, err := verifyReference(root, signature, reference)
+		if err != nil {
+			return nil, err
+		}
+		covered[target] = true
+	}
+
+	// La firma debe cubrir tanto el comprobante como las propiedades firmadas
+	if !covered[root] {
+		return nil, fmt.Errorf("%w: comprobante", ErrReferenceNotFound)
+	}
+	if !covered[properties] {
+		return nil, fmt.Errorf("%w: SignedProperties", ErrReferenceNotFound)
+	}
+
+	result, err := signer(signature, properties)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := decodeBase64(signature.child("SignatureValue"))
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	if err := verifyValue(result.Certificate, hash, canonicalize(signedInfo, nil), value); err != nil {
+		return nil, err
+	}
+
+	signingTime := properties.findNamed("SigningTime")
+	if signingTime == nil {
+		return nil, ErrInvalidSigningTime
+	}
+
+	result.SigningTime, err = time.Parse(time.RFC3339, signingTime.text())
+	if err != nil {
+		return nil, ErrInvalidSigningTime
+	}
+
+	store := opts.TrustStore
+	if store == nil {
+		if store, err = cert.DefaultTrustStore(); err != nil {
+			return nil, err
+		}
+	}
+
+	chains, err := store.Verify(result.Certificate, result.Intermediates, result.SigningTime)
+	if err != nil {
+		return nil, err
+	}
+	result.Chain = chains[0]
+
+	if opts.CRLs != nil {
+		issuer := result.Chain[len(result.Chain)-1]
+		if len(result.Chain) > 1 {
+			issuer = result.Chain[1]
+		}
+
+		revocation, err := opts.CRLs.Check(result.Certificate, issuer, result.SigningTime)
+		if err != nil {
+			return nil, err
+		}
+		if revocation != nil {
+			return nil, revocation
+		}
+	}
+
+	return result, nil
+}
+
+// verifyReference comprueba el resumen de una referencia y devuelve el elemento firmado.
+func verifyReference(root, signature, reference *node) (*node, error) {
+	uri := reference.attr("URI")
+
+	var target *node
+	switch {
+	case uri == "":
+		target = root
+	case strings.HasPrefix(uri, "#"):
+		target = root.findID(uri[1:])
+	}
+	if target == nil {
+		return nil, fmt.Errorf("%w: %s", ErrReferenceNotFound, uri)
+	}
+
+	var exclude *node
+	if transforms := reference.child("Transforms"); transforms != nil {
+		for _, transform := range transforms.childrenNamed("Transform") {
+			switch method := algorithm(transform); method {
+			case AlgorithmEnveloped:
+				exclude = signature
+			case AlgorithmC14N:
+			default:
+				return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, method)
+			}
+		}
+	}
+
+	hash, ok := digestMethods[algorithm(reference.child("DigestMethod"))]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, algorithm(reference.child("DigestMethod")))
+	}
+
+	expected, err := decodeBase64(reference.child("DigestValue"))
+	if err != nil || !bytes.Equal(expected, digest(hash, canonicalize(target, exclude))) {
+		return nil, fmt.Errorf("%w: %s", ErrDigestMismatch, uri)
+	}
+
+	return target, nil
+}
+
+// signer obtiene el certificado del firmante de KeyInfo y comprueba que coincida con
+// el declarado en SigningCertificate.
+func signer(signature, properties *node) (*Signature, error) {
+	keyInfo := signature.child("KeyInfo")
+	if keyInfo == nil {
+		return nil, ErrCertificateNotFound
+	}
+
+	var certs []*x509.Certificate
+	keyInfo.find(func(e *node) bool {
+		if e.name.Local == "X509Certificate" {
+			der, err := base64.StdEncoding.DecodeString(compact(e.text()))
+			if err == nil {
+				if c, err := x509.ParseCertificate(der); err == nil {
+					certs = append(certs, c)
+				}
+			}
+		}
+		return false
+	})
+	if len(certs) == 0 {
+		return nil, ErrCertificateNotFound
+	}
+
+	certDigest := properties.findNamed("CertDigest")
+	if certDigest == nil {
+		return nil, ErrSigningCertificate
+	}
+
+	hash, ok := digestMethods[algorithm(certDigest.child("DigestMethod"))]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedMethod, algorithm(certDigest.child("DigestMethod")))
+	}
+
+	expected, err := decodeBase64(certDigest.child("DigestValue"))
+	if err != nil {
+		return nil, ErrSigningCertificate
+	}
+
+	result := &Signature{}
+	for _, c := range certs {
+		if result.Certificate == nil && bytes.Equal(expected, digest(hash, c.Raw)) {
+			result.Certificate = c
+			continue
+		}
+		result.Intermediates = append(result.Intermediates, c)
+	}
+
+	if result.Certificate == nil {
+		return nil, ErrSigningCertificate
+	}
+
+	return result, nil
+}
+
+// verifyValue comprueba el valor de la firma sobre el SignedInfo canonicalizado.
+func verifyValue(c *x509.Certificate, hash crypto.Hash, signedInfo, value []byte) error {
+	key, ok := c.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("%w: %T", ErrUnsupportedMethod, c.PublicKey)
+	}
+
+	if err := rsa.VerifyPKCS1v15(key, hash, digest(hash, signedInfo), value); err != nil {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// algorithm devuelve el atributo Algorithm del elemento, o vacío si no existe.
+func algorithm(n *node) string {
+	if n == nil {
+		return ""
+	}
+
+	return n.attr("Algorithm")
+}
+
+// decodeBase64 decodifica el contenido base64 de un elemento, ignorando saltos de línea.
+func decodeBase64(n *node) ([]byte, error) {
+	if n == nil {
+		return nil, ErrInvalidDocument
+	}
+
+	return base64.StdEncoding.DecodeString(compact(n.text()))
+}
+
+// compact elimina los espacios en blanco de una cadena.
+func compact(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
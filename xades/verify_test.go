@@ -0,0 +1,198 @@
+package xades
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pinzlab/sricore/cert"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKey es la clave RSA compartida por los certificados de prueba.
+var testKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+
+// testVoucher es un comprobante mínimo usado en las pruebas.
+const testVoucher = `<?xml version="1.0" encoding="UTF-8"?>
+<factura id="comprobante" version="1.1.0">
+  <infoTributaria>
+    <ambiente>1</ambiente>
+    <razonSocial>EMPRESA ELECTRICA RIOBAMBA S.A.</razonSocial>
+    <ruc>0690000512001</ruc>
+  </infoTributaria>
+</factura>`
+
+// newTestPKI crea una raíz autofirmada y un certificado de firma emitido por ella.
+func newTestPKI(t *testing.T, notBefore, notAfter time.Time) (root, leaf *x509.Certificate) {
+	t.Helper()
+
+	rootTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "AC RAIZ PRUEBAS", Organization: []string{"BANCO CENTRAL DEL ECUADOR"}},
+		NotBefore:             notBefore.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter.Add(365 * 24 * time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, rootTemplate, rootTemplate, &testKey.PublicKey, testKey)
+	require.NoError(t, err)
+	root, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "JUAN PEREZ"},
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+	}
+	der, err = x509.CreateCertificate(rand.Reader, leafTemplate, root, &testKey.PublicKey, testKey)
+	require.NoError(t, err)
+	leaf, err = x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return root, leaf
+}
+
+// signTestVoucher firma el comprobante con la clave de prueba en la fecha indicada.
+func signTestVoucher(t *testing.T, doc string, leaf *x509.Certificate, signingTime time.Time) []byte {
+	t.Helper()
+
+	signer, err := NewFileSigner(&cert.Certificate{Leaf: leaf, PrivateKey: testKey})
+	require.NoError(t, err)
+
+	signed, err := signAt(context.Background(), []byte(doc), signer, signingTime)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	root, leaf := newTestPKI(t, now.Add(-24*time.Hour), now.Add(24*time.Hour))
+
+	store := cert.NewTrustStore()
+	store.AddCertificate(root)
+
+	doc := signTestVoucher(t, testVoucher, leaf, now)
+
+	signature, err := Verify(doc, VerifyOptions{TrustStore: store})
+	require.NoError(t, err)
+	assert.Equal(t, leaf.Raw, signature.Certificate.Raw)
+	assert.Equal(t, now.Unix(), signature.SigningTime.Unix())
+	assert.Len(t, signature.Chain, 2)
+}
+
+func TestVerify_Errors(t *testing.T) {
+	now := time.Now()
+	root, leaf := newTestPKI(t, now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	store := cert.NewTrustStore()
+	store.AddCertificate(root)
+
+	t.Run("untrusted root", func(t *testing.T) {
+		doc := signTestVoucher(t, testVoucher, leaf, now)
+		_, err := Verify(doc, VerifyOptions{TrustStore: cert.NewTrustStore()})
+		assert.ErrorIs(t, err, cert.ErrUntrustedChain)
+	})
+
+	t.Run("signed outside validity", func(t *testing.T) {
+		doc := signTestVoucher(t, testVoucher, leaf, now.Add(-48*time.Hour))
+		_, err := Verify(doc, VerifyOptions{TrustStore: store})
+		assert.ErrorIs(t, err, cert.ErrUntrustedChain)
+	})
+
+	t.Run("tampered voucher", func(t *testing.T) {
+		doc := signTestVoucher(t, testVoucher, leaf, now)
+		doc = []byte(strings.Replace(string(doc), "RIOBAMBA", "QUITO", 1))
+		_, err := Verify(doc, VerifyOptions{TrustStore: store})
+		assert.ErrorIs(t, err, ErrDigestMismatch)
+	})
+
+	t.Run("revoked before signing", func(t *testing.T) {
+		der, err := x509.CreateRevocationList(rand.Reader, &x509.RevocationList{
+			Number:     big.NewInt(1),
+			ThisUpdate: now.Add(time.Minute),
+			NextUpdate: now.Add(24 * time.Hour),
+			RevokedCertificateEntries: []x509.RevocationListEntry{
+				{SerialNumber: leaf.SerialNumber, RevocationTime: now.Add(-time.Hour), ReasonCode: int(cert.ReasonKeyCompromise)},
+			},
+		}, root, testKey)
+		require.NoError(t, err)
+
+		crls := cert.NewCRLStore()
+		require.NoError(t, crls.Add(der))
+
+		doc := signTestVoucher(t, testVoucher, leaf, now)
+		_, err = Verify(doc, VerifyOptions{TrustStore: store, CRLs: crls})
+
+		var revocation *cert.Revocation
+		require.ErrorAs(t, err, &revocation)
+		assert.Equal(t, cert.ReasonKeyCompromise, revocation.Reason)
+		assert.ErrorIs(t, err, cert.ErrCertificateRevoked)
+	})
+
+	t.Run("unsigned voucher", func(t *testing.T) {
+		_, err := Verify([]byte(testVoucher), VerifyOptions{TrustStore: store})
+		assert.ErrorIs(t, err, ErrSignatureNotFound)
+	})
+
+	t.Run("default trust store", func(t *testing.T) {
+		doc := signTestVoucher(t, testVoucher, leaf, now)
+		_, err := Verify(doc, VerifyOptions{})
+		if _, defaultErr := cert.DefaultTrustStore(); defaultErr != nil {
+			assert.ErrorIs(t, err, cert.ErrNoTrustedRoots)
+		} else {
+			assert.ErrorIs(t, err, cert.ErrUntrustedChain)
+		}
+	})
+}
+
+func TestVerify_DefaultTrustStore(t *testing.T) {
+	now := time.Now()
+	root, leaf := newTestPKI(t, now.Add(-24*time.Hour), now.Add(24*time.Hour))
+	doc := signTestVoucher(t, testVoucher, leaf, now)
+
+	store := cert.NewTrustStore()
+	store.AddCertificate(root)
+	cert.SetDefaultTrustStore(store)
+	t.Cleanup(func() { cert.SetDefaultTrustStore(nil) })
+
+	signature, err := Verify(doc, VerifyOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, root.Raw, signature.Chain[1].Raw)
+}
+
+// TestVerify_Fixture verifica un comprobante firmado con xmllint y OpenSSL por
+// testdata/generar.sh, sin pasar por Sign.
+func TestVerify_Fixture(t *testing.T) {
+	doc, err := os.ReadFile("testdata/factura-firmada.xml")
+	require.NoError(t, err)
+
+	roots, err := os.ReadFile("testdata/ac.pem")
+	require.NoError(t, err)
+
+	store := cert.NewTrustStore()
+	require.NoError(t, store.AddPEM(roots))
+
+	signature, err := Verify(doc, VerifyOptions{TrustStore: store})
+	require.NoError(t, err)
+	assert.Equal(t, "JUAN PEREZ", signature.Certificate.Subject.CommonName)
+	assert.Equal(t, time.Date(2025, 1, 15, 15, 30, 0, 0, time.UTC), signature.SigningTime.UTC())
+	assert.Len(t, signature.Chain, 2)
+
+	t.Run("tampered voucher", func(t *testing.T) {
+		tampered := bytes.Replace(doc, []byte("<totalSinImpuestos>10.00"), []byte("<totalSinImpuestos>1.00"), 1)
+		_, err := Verify(tampered, VerifyOptions{TrustStore: store})
+		assert.ErrorIs(t, err, ErrDigestMismatch)
+	})
+}